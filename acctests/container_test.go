@@ -202,22 +202,30 @@ func getContainer(ctx context.Context, tgc *tg.Client, entity string, entityID s
 	return res, err
 }
 
+// intOrZero is the value p points at, or 0 when it's nil.
+func intOrZero(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
 func checkLimits(limits *tg.ContainerLimits) error {
 	switch {
 	case limits == nil:
 		return fmt.Errorf("limits is nil")
-	case limits.CPUMax != 25:
-		return fmt.Errorf("expected container limits cpu_max to be '25', got '%d'", limits.CPUMax)
-	case limits.IORBPS != 15:
-		return fmt.Errorf("expected container limits io_rbps to be '15', got '%d'", limits.IORBPS)
-	case limits.IORIOPS != 11:
-		return fmt.Errorf("expected container limits io_riops to be '11', got '%d'", limits.IORIOPS)
-	case limits.IOWBPS != 16:
-		return fmt.Errorf("expected container limits io_wbps to be '16', got '%d'", limits.IOWBPS)
-	case limits.MemHigh != 25:
-		return fmt.Errorf("expected container limits mem_high to be '25', got '%d'", limits.MemHigh)
-	case limits.MemMax != 45:
-		return fmt.Errorf("expected container limits mem_max to be '45', got '%d'", limits.MemMax)
+	case intOrZero(limits.CPUMax) != 25:
+		return fmt.Errorf("expected container limits cpu_max to be '25', got '%d'", intOrZero(limits.CPUMax))
+	case intOrZero(limits.IORBPS) != 15:
+		return fmt.Errorf("expected container limits io_rbps to be '15', got '%d'", intOrZero(limits.IORBPS))
+	case intOrZero(limits.IORIOPS) != 11:
+		return fmt.Errorf("expected container limits io_riops to be '11', got '%d'", intOrZero(limits.IORIOPS))
+	case intOrZero(limits.IOWBPS) != 16:
+		return fmt.Errorf("expected container limits io_wbps to be '16', got '%d'", intOrZero(limits.IOWBPS))
+	case intOrZero(limits.MemHigh) != 25:
+		return fmt.Errorf("expected container limits mem_high to be '25', got '%d'", intOrZero(limits.MemHigh))
+	case intOrZero(limits.MemMax) != 45:
+		return fmt.Errorf("expected container limits mem_max to be '45', got '%d'", intOrZero(limits.MemMax))
 	case len(limits.Limits) != 2:
 		return fmt.Errorf("expected container limits to have 2 entries, got '%d'", len(limits.Limits))
 	case limits.Limits[0].Type != "nofile":
@@ -134,8 +134,8 @@ func checkNetworkConfig(ctx context.Context, provider *schema.Provider, name str
 			return fmt.Errorf("expected NIC to be ens192, got %s", n.Config.Network.Interfaces[0].NIC)
 		case n.Config.Network.Interfaces[0].VRF != "blue":
 			return fmt.Errorf("expected VRF to be blue, got %s", n.Config.Network.Interfaces[0].VRF)
-		case n.Config.Network.Interfaces[0].DHCP != false:
-			return fmt.Errorf("expected DHCP to be false, got %v", n.Config.Network.Interfaces[0].DHCP)
+		case n.Config.Network.Interfaces[0].DHCP != nil && *n.Config.Network.Interfaces[0].DHCP:
+			return fmt.Errorf("expected DHCP to be false, got %v", *n.Config.Network.Interfaces[0].DHCP)
 		case n.Config.Network.Interfaces[0].Gateway != "10.20.10.1":
 			return fmt.Errorf("expected gateway to be 10.20.10.1, got %s", n.Config.Network.Interfaces[0].Gateway)
 		case n.Config.Network.Interfaces[0].IP != "10.20.10.50/24":
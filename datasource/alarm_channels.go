@@ -0,0 +1,18 @@
+package datasource
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func AlarmChannels() *schema.Resource {
+	return majordomo.NewListDataSource(majordomo.ListDataSourceArgs[tg.AlarmChannel, hcl.AlarmChannel]{
+		Description: "Fetch alarm channels.",
+		IndexURL:    func(_ *tg.Client, _ *schema.ResourceData) string { return "/v2/alarm-channel" },
+		Attribute:   "alarm_channels",
+		Element:     AlarmChannel().Schema,
+		RemoteID:    func(c tg.AlarmChannel) string { return c.UID },
+	}).Resource()
+}
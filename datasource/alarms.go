@@ -0,0 +1,18 @@
+package datasource
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func Alarms() *schema.Resource {
+	return majordomo.NewListDataSource(majordomo.ListDataSourceArgs[tg.Alarm, hcl.Alarm]{
+		Description: "Fetch alarms.",
+		IndexURL:    func(_ *tg.Client, _ *schema.ResourceData) string { return "/v2/alarm" },
+		Attribute:   "alarms",
+		Element:     Alarm().Schema,
+		RemoteID:    func(a tg.Alarm) string { return a.UID },
+	}).Resource()
+}
@@ -0,0 +1,19 @@
+package datasource
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func Clusters() *schema.Resource {
+	return majordomo.NewListDataSource(majordomo.ListDataSourceArgs[tg.Cluster, hcl.Cluster]{
+		Description: "Fetch clusters. Cluster members aren't listed; use the `tg_cluster` data source for those.",
+		IndexURL:    func(_ *tg.Client, _ *schema.ResourceData) string { return "/cluster" },
+		Attribute:   "clusters",
+		Element:     Cluster().Schema,
+		RemoteID:    func(c tg.Cluster) string { return c.FQDN },
+		Tags:        func(c tg.Cluster) map[string]string { return c.Tags },
+	}).Resource()
+}
@@ -2,7 +2,6 @@ package datasource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -69,9 +68,8 @@ func (r *kvmImage) Read(ctx context.Context, d *schema.ResourceData, meta any) d
 
 	tgimg := tg.KVMImage{}
 	err = tgc.Get(ctx, tf.ResourceURL(tf.UID), &tgimg)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -2,7 +2,6 @@ package datasource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -79,9 +78,8 @@ func (r *kvmVolume) Read(ctx context.Context, d *schema.ResourceData, meta any)
 
 	tgimg := tg.KVMVolume{}
 	err = tgc.Get(ctx, tf.ResourceURL(), &tgimg)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
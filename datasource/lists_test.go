@@ -0,0 +1,76 @@
+package datasource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func listPortal(t *testing.T) *tg.Client {
+	t.Helper()
+
+	responses := map[string]string{
+		"/api/org/mine":         `{"uid":"org-1","domain":"example.trustgrid.io"}`,
+		"/api/v2/alarm":         `[{"uid":"a1","name":"Node down","enabled":true,"channels":["c1"],"types":["Node Disconnect"],"tags":["env"]}]`,
+		"/api/v2/alarm-channel": `[{"uid":"c1","name":"Ops","emails":"ops@example.com,noc@example.com","slackChannel":"#ops","slackWebhook":"https://hooks.example.com"}]`,
+		"/api/cluster":          `[{"name":"edge","fqdn":"edge.example.com","health":"healthy","tags":{"env":"prod"}}]`,
+		"/api/v2/domain/example.trustgrid.io/network/vnet1/network-group": `[{"name":"web","description":"Web tier"}]`,
+	}
+
+	mux := http.NewServeMux()
+	for path, body := range responses {
+		mux.HandleFunc("GET "+path, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, body)
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	tgc, err := tg.NewClient(context.Background(), tg.ClientParams{
+		APIKey:     "key",
+		APISecret:  "secret",
+		BaseURL:    srv.URL + "/api/",
+		HTTPClient: srv.Client(),
+	})
+	require.NoError(t, err)
+	return tgc
+}
+
+func TestListDataSources(t *testing.T) {
+	tgc := listPortal(t)
+
+	for _, tc := range []struct {
+		name      string
+		r         *schema.Resource
+		args      map[string]any
+		attribute string
+		want      map[string]any
+	}{
+		{"alarms", Alarms(), nil, "alarms", map[string]any{"uid": "a1", "name": "Node down", "enabled": true}},
+		{"alarm channels", AlarmChannels(), nil, "alarm_channels", map[string]any{"uid": "c1", "name": "Ops", "emails": []any{"ops@example.com", "noc@example.com"}}},
+		{"clusters", Clusters(), map[string]any{"tags": map[string]any{"env": "prod"}}, "clusters", map[string]any{"fqdn": "edge.example.com", "health": "healthy"}},
+		{"virtual network groups", VNetGroups(), map[string]any{"network": "vnet1"}, "groups", map[string]any{"name": "web", "description": "Web tier", "network": "vnet1"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.r.InternalValidate(nil, false))
+
+			d := schema.TestResourceDataRaw(t, tc.r.Schema, tc.args)
+			diags := tc.r.ReadContext(context.Background(), d, tgc)
+			require.False(t, diags.HasError(), "%v", diags)
+
+			items, ok := d.Get(tc.attribute).([]any)
+			require.True(t, ok)
+			require.Len(t, items, 1)
+			for k, v := range tc.want {
+				assert.Equal(t, v, items[0].(map[string]any)[k], k) //nolint: errcheck // just trusting TF validation here
+			}
+		})
+	}
+}
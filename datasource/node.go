@@ -2,7 +2,6 @@ package datasource
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -64,12 +63,6 @@ func (ds *dsNode) Read(ctx context.Context, d *schema.ResourceData, meta any) di
 		return diag.FromErr(err)
 	}
 
-	if tf.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(tf.Timeout)*time.Second)
-		defer cancel()
-	}
-
 	url := "/node/" + tf.UID
 	if tf.UID == "" {
 		url = "/node/by-fqdn/" + tf.FQDN
@@ -77,16 +70,28 @@ func (ds *dsNode) Read(ctx context.Context, d *schema.ResourceData, meta any) di
 
 	node := tg.Node{}
 
-	for {
-		if err := tgc.Get(ctx, url, &node); err != nil {
-			var nferr *tg.NotFoundError
-			if tf.Timeout > 0 && errors.As(err, &nferr) {
-				time.Sleep(30 * time.Second)
-				continue
-			}
-			return diag.FromErr(err)
+	// With a timeout, a node that hasn't registered yet is waited for instead of being an error,
+	// and every check reads from the portal rather than the read cache.
+	poller := tg.Poller{
+		Interval:    5 * time.Second,
+		MaxInterval: 30 * time.Second,
+		Timeout:     time.Duration(tf.Timeout) * time.Second,
+	}
+	err = poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		if tf.Timeout > 0 {
+			ctx = tg.WithoutCache(ctx)
 		}
-		break
+		err := tgc.Get(ctx, url, &node)
+		switch {
+		case tf.Timeout > 0 && tg.IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	tf.UID = node.UID
@@ -0,0 +1,31 @@
+package datasource
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func VNetGroups() *schema.Resource {
+	return majordomo.NewListDataSource(majordomo.ListDataSourceArgs[tg.VNetGroup, hcl.VNetGroup]{
+		Description: "Fetch the groups of a virtual network.",
+		IndexURL:    vnetIndexURL("network-group"),
+		Params:      vnetParams(),
+		Attribute:   "groups",
+		Element: map[string]*schema.Schema{
+			"name": {
+				Description: "Group name",
+				Type:        schema.TypeString,
+			},
+			"description": {
+				Description: "Group description",
+				Type:        schema.TypeString,
+			},
+			"network": {
+				Description: "Virtual network name",
+				Type:        schema.TypeString,
+			},
+		},
+	}).Resource()
+}
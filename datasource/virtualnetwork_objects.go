@@ -0,0 +1,50 @@
+package datasource
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// vnetParams are the arguments of data sources listing a virtual network's children.
+func vnetParams() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"network": {
+			Description: "Virtual network name",
+			Type:        schema.TypeString,
+			Required:    true,
+		},
+	}
+}
+
+// vnetIndexURL returns a function giving the URL of the `kind` children of the data source's network.
+func vnetIndexURL(kind string) func(*tg.Client, *schema.ResourceData) string {
+	return func(tgc *tg.Client, d *schema.ResourceData) string {
+		network, _ := d.Get("network").(string)
+		return "/v2/domain/" + tgc.Domain + "/network/" + network + "/" + kind
+	}
+}
+
+func VNetObjects() *schema.Resource {
+	return majordomo.NewListDataSource(majordomo.ListDataSourceArgs[tg.VNetObject, hcl.VNetObject]{
+		Description: "Fetch the objects of a virtual network.",
+		IndexURL:    vnetIndexURL("network-object"),
+		Params:      vnetParams(),
+		Attribute:   "objects",
+		Element: map[string]*schema.Schema{
+			"name": {
+				Description: "Object name",
+				Type:        schema.TypeString,
+			},
+			"cidr": {
+				Description: "Object CIDR",
+				Type:        schema.TypeString,
+			},
+			"network": {
+				Description: "Virtual network name",
+				Type:        schema.TypeString,
+			},
+		},
+	}).Resource()
+}
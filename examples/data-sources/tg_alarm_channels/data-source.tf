@@ -0,0 +1,3 @@
+data "tg_alarm_channels" "ops" {
+  name_regex = "^ops-"
+}
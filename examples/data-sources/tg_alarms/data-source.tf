@@ -0,0 +1,6 @@
+data "tg_alarms" "enabled" {
+  filter {
+    name   = "enabled"
+    values = ["true"]
+  }
+}
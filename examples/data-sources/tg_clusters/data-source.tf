@@ -0,0 +1,5 @@
+data "tg_clusters" "prod" {
+  tags = {
+    env = "prod"
+  }
+}
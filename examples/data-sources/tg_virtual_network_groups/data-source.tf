@@ -0,0 +1,3 @@
+data "tg_virtual_network_groups" "all" {
+  network = "my-vnet"
+}
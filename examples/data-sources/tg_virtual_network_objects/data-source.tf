@@ -0,0 +1,4 @@
+data "tg_virtual_network_objects" "web" {
+  network    = "my-vnet"
+  name_regex = "^web-"
+}
@@ -0,0 +1,2 @@
+# Alarms are imported by UID
+terraform import tg_alarm.example 0b1f5bd1-1a4d-4bd3-a4a4-1c1b3b4d4f5e
@@ -0,0 +1,2 @@
+# Alarm channels are imported by UID
+terraform import tg_alarm_channel.example 8f0e2c7a-6d55-4c5e-9b1e-2a3b4c5d6e7f
@@ -0,0 +1,2 @@
+# Apps are imported by app ID
+terraform import tg_app.example 5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a
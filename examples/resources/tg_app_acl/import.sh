@@ -0,0 +1,2 @@
+# ACLs are imported by app ID and ACL ID
+terraform import tg_app_acl.example 5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a/9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d
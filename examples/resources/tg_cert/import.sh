@@ -0,0 +1,3 @@
+# Certificates are imported by FQDN. The body, chain and private key are not returned by the portal,
+# so the first apply after import writes them from the configuration.
+terraform import tg_cert.example app.example.com
@@ -0,0 +1,2 @@
+# Clusters are imported by FQDN
+terraform import tg_cluster.example my-cluster.example.trustgrid.io
@@ -0,0 +1,2 @@
+# Cluster connectors are imported as <cluster_fqdn>:<connector_id>
+terraform import tg_cluster_connector.example my-cluster.example.trustgrid.io:connector-id
@@ -0,0 +1,2 @@
+# Cluster services are imported as <cluster_fqdn>:<service_id>
+terraform import tg_cluster_service.example my-cluster.example.trustgrid.io:service-id
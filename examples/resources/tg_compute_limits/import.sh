@@ -0,0 +1,2 @@
+# Compute limits are imported by node ID
+terraform import tg_compute_limits.example 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d
@@ -0,0 +1,4 @@
+# Containers are imported by node ID or cluster FQDN and container ID.
+# vrf is not returned by the portal, so it is left empty in the imported state.
+terraform import tg_container.node node:6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f
+terraform import tg_container.cluster cluster:edge.example.trustgrid.io/0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f
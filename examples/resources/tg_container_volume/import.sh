@@ -0,0 +1,3 @@
+# Container volumes are imported by node ID or cluster FQDN and volume name
+terraform import tg_container_volume.node node:6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/data
+terraform import tg_container_volume.cluster cluster:edge.example.trustgrid.io/data
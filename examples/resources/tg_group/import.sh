@@ -0,0 +1,2 @@
+# Groups are imported by group UID
+terraform import tg_group.example 2c4b6e8a-1d3f-4a5b-8c7d-9e0f1a2b3c4d
@@ -0,0 +1,3 @@
+# OpenID config is imported by IDP UID. The portal does not return the client secret,
+# so the first plan after import updates secret from your configuration.
+terraform import tg_idp_openid_config.example 0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f
@@ -0,0 +1,2 @@
+# SAML config is imported by IDP UID
+terraform import tg_idp_saml_config.example 0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f
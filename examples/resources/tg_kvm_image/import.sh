@@ -0,0 +1,2 @@
+# KVM images are imported by node ID and image ID
+terraform import tg_kvm_image.example node:6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f
@@ -0,0 +1,2 @@
+# KVM volumes are imported by node ID and volume name
+terraform import tg_kvm_volume.example node:6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/disk1
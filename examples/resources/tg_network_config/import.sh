@@ -0,0 +1,3 @@
+# Network config is imported by node ID or cluster FQDN
+terraform import tg_network_config.node 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d
+terraform import tg_network_config.cluster edge.example.trustgrid.io
@@ -0,0 +1,2 @@
+# Cluster config is imported by node ID
+terraform import tg_node_cluster_config.example 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d
@@ -0,0 +1,2 @@
+# Node connectors are imported as <node_id>:<connector_id>
+terraform import tg_node_connector.example 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d:connector-id
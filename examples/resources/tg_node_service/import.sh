@@ -0,0 +1,2 @@
+# Node services are imported as <node_id>:<service_id>
+terraform import tg_node_service.example 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d:service-id
@@ -0,0 +1,2 @@
+# Policies are imported by name
+terraform import tg_policy.example my-policy
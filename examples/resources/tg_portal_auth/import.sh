@@ -0,0 +1,2 @@
+# Portal authentication is imported by portal domain
+terraform import tg_portal_auth.example mycompany.trustgrid.io
@@ -0,0 +1,2 @@
+# Service users are imported by name
+terraform import tg_serviceuser.example my-service-user
@@ -0,0 +1,2 @@
+# Users are imported by email
+terraform import tg_user.example jane@example.com
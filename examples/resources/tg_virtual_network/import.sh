@@ -0,0 +1,2 @@
+# Virtual networks are imported by name
+terraform import tg_virtual_network.example my-vnet
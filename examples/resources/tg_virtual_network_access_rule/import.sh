@@ -0,0 +1,5 @@
+# Access rules are imported by network name and rule UID...
+terraform import tg_virtual_network_access_rule.example my-vnet/2c4b6e8a-1d3f-4a5b-8c7d-9e0f1a2b3c4d
+
+# ...or by network name and line number
+terraform import tg_virtual_network_access_rule.example my-vnet/100
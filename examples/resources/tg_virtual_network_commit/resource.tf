@@ -0,0 +1,18 @@
+provider "tg" {
+  defer_network_commits = true
+}
+
+resource "tg_virtual_network_route" "routes" {
+  for_each = toset(["10.10.1.0/24", "10.10.2.0/24", "10.10.3.0/24"])
+
+  network      = "my-virtual-network"
+  dest         = "my-node"
+  network_cidr = each.key
+  metric       = 10
+}
+
+resource "tg_virtual_network_commit" "commit" {
+  network = "my-virtual-network"
+
+  depends_on = [tg_virtual_network_route.routes]
+}
@@ -0,0 +1,2 @@
+# Network groups are imported by network name and group name
+terraform import tg_virtual_network_group.example my-vnet/dmz
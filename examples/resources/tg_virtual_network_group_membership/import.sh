@@ -0,0 +1,2 @@
+# Group memberships are imported by network name, group name and object name
+terraform import tg_virtual_network_group_membership.example my-vnet/dmz/web-servers
@@ -0,0 +1,2 @@
+# Network objects are imported by network name and object name
+terraform import tg_virtual_network_object.example my-vnet/web-servers
@@ -0,0 +1,2 @@
+# Port forwards are imported by network name and port forward UID
+terraform import tg_virtual_network_port_forward.example my-vnet/2c4b6e8a-1d3f-4a5b-8c7d-9e0f1a2b3c4d
@@ -0,0 +1,5 @@
+# Routes are imported by network name and route UID...
+terraform import tg_virtual_network_route.example my-vnet/2c4b6e8a-1d3f-4a5b-8c7d-9e0f1a2b3c4d
+
+# ...or by network name, network_cidr and dest, if only one route matches
+terraform import tg_virtual_network_route.example my-vnet/10.10.0.0/24+edge-node
@@ -0,0 +1,3 @@
+# Attachments are imported by node ID or cluster FQDN and network name
+terraform import tg_vpn_attachment.node node:6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/my-vnet
+terraform import tg_vpn_attachment.cluster cluster:edge.example.trustgrid.io/my-vnet
@@ -0,0 +1,5 @@
+# Node routes are imported as node:<node_id>/<network_name>/<uid>
+terraform import tg_vpn_dynamic_import_route.example node:6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/my-vnet/route-uid
+
+# Cluster routes are imported as cluster:<cluster_fqdn>/<network_name>/<uid>
+terraform import tg_vpn_dynamic_import_route.example cluster:my-cluster.example.trustgrid.io/my-vnet/route-uid
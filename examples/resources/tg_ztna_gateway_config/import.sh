@@ -0,0 +1,4 @@
+# ZTNA gateway config is imported by node ID or cluster FQDN. wg_key is not returned by the API,
+# so it stays empty in state until the next apply.
+terraform import tg_ztna_gateway_config.node 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d
+terraform import tg_ztna_gateway_config.cluster edge.example.trustgrid.io
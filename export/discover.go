@@ -0,0 +1,322 @@
+package export
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// object is a portal object to export as a resource.
+type object struct {
+	Type string // resource type, e.g. tg_virtual_network
+	Name string // human-readable name the resource label is derived from
+	ID   string // import ID, in one of the formats the resource's importer accepts
+}
+
+// listed is the part of a portal list entry needed to name and import it. Some APIs key objects by
+// `uid` and some by `id`.
+type listed struct {
+	UID         string `json:"uid"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (l listed) key() string {
+	if l.UID != "" {
+		return l.UID
+	}
+	return l.ID
+}
+
+// discoverer lists the objects in an org.
+type discoverer struct {
+	tgc   *tg.Client
+	warnf func(format string, args ...any)
+}
+
+// discover returns every object it could list. Listing errors are reported and skipped, so an org
+// without, say, ZTNA apps enabled still exports everything else.
+func (x *discoverer) discover(ctx context.Context) []object {
+	var objects []object
+	for _, list := range []func(context.Context) []object{
+		x.nodes,
+		x.clusters,
+		x.virtualNetworks,
+		x.apps,
+		x.idps,
+		x.policies,
+		x.users,
+		x.serviceUsers,
+		x.groups,
+		x.alarms,
+	} {
+		objects = append(objects, list(ctx)...)
+	}
+	return objects
+}
+
+// get lists url into out. A not-found error is an empty list, any other error is reported.
+func (x *discoverer) get(ctx context.Context, url string, out any) bool {
+	err := x.tgc.Get(ctx, url, out)
+	switch {
+	case tg.IsNotFound(err):
+		return false
+	case err != nil:
+		x.warnf("listing %s: %s", url, err)
+		return false
+	}
+	return true
+}
+
+func (x *discoverer) nodes(ctx context.Context) []object {
+	var nodes []tg.Node
+	if !x.get(ctx, "/node", &nodes) {
+		return nil
+	}
+
+	var objects []object
+	for _, n := range nodes {
+		objects = append(objects,
+			object{Type: "tg_node_state", Name: n.Name, ID: n.UID},
+			object{Type: "tg_network_config", Name: n.Name, ID: n.UID},
+		)
+		if len(n.Tags) > 0 {
+			objects = append(objects, object{Type: "tg_tagging", Name: n.Name, ID: n.UID})
+		}
+		objects = append(objects, x.execObjects(ctx, "/v2/node/"+n.UID, "node:"+n.UID, n.Name)...)
+	}
+	return objects
+}
+
+func (x *discoverer) clusters(ctx context.Context) []object {
+	var clusters []tg.Cluster
+	if !x.get(ctx, "/cluster", &clusters) {
+		return nil
+	}
+
+	var objects []object
+	for _, c := range clusters {
+		objects = append(objects,
+			object{Type: "tg_cluster", Name: c.Name, ID: c.FQDN},
+			object{Type: "tg_network_config", Name: c.Name, ID: c.FQDN},
+		)
+		if len(c.Tags) > 0 {
+			objects = append(objects, object{Type: "tg_tagging", Name: c.Name, ID: c.FQDN})
+		}
+		objects = append(objects, x.execObjects(ctx, "/v2/cluster/"+c.FQDN, "cluster:"+c.FQDN, c.Name)...)
+	}
+	return objects
+}
+
+// execObjects lists the containers, volumes and virtual network attachments of the node or cluster
+// at base. prefix is the `node:<node_id>` or `cluster:<cluster_fqdn>` part of their import IDs.
+func (x *discoverer) execObjects(ctx context.Context, base, prefix, owner string) []object {
+	var objects []object
+
+	var containers []tg.Container
+	if x.get(ctx, base+"/exec/container", &containers) {
+		for _, c := range containers {
+			objects = append(objects, object{Type: "tg_container", Name: owner + "_" + c.Name, ID: prefix + "/" + c.ID})
+		}
+	}
+
+	var volumes []tg.Volume
+	if x.get(ctx, base+"/exec/volume", &volumes) {
+		for _, v := range volumes {
+			objects = append(objects, object{Type: "tg_container_volume", Name: owner + "_" + v.Name, ID: prefix + "/" + v.Name})
+		}
+	}
+
+	var attachments []tg.VPNAttachment
+	if x.get(ctx, base+"/vpn", &attachments) {
+		for _, a := range attachments {
+			objects = append(objects, object{Type: "tg_virtual_network_attachment", Name: owner + "_" + a.NetworkName, ID: prefix + "/" + a.NetworkName})
+		}
+	}
+
+	return objects
+}
+
+func (x *discoverer) virtualNetworks(ctx context.Context) []object {
+	var vnets []tg.VirtualNetwork
+	if !x.get(ctx, "/v2/domain/"+x.tgc.Domain+"/network", &vnets) {
+		return nil
+	}
+
+	var objects []object
+	for _, vn := range vnets {
+		network := vn.Name
+		base := "/v2/domain/" + x.tgc.Domain + "/network/" + network
+		objects = append(objects, object{Type: "tg_virtual_network", Name: network, ID: network})
+
+		var routes []tg.VNetRoute
+		if x.get(ctx, base+"/route", &routes) {
+			for _, r := range routes {
+				objects = append(objects, object{Type: "tg_virtual_network_route", Name: network + "_" + r.Dest + "_" + r.NetworkCIDR, ID: network + "/" + r.UID})
+			}
+		}
+
+		var rules []tg.VNetAccessRule
+		if x.get(ctx, base+"/access-policy", &rules) {
+			for _, r := range rules {
+				objects = append(objects, object{Type: "tg_virtual_network_access_rule", Name: network + "_" + strconv.Itoa(r.LineNumber), ID: network + "/" + r.UID})
+			}
+		}
+
+		var forwards []tg.VNetPortForward
+		if x.get(ctx, base+"/port-forwarding", &forwards) {
+			for _, pf := range forwards {
+				objects = append(objects, object{Type: "tg_virtual_network_port_forward", Name: network + "_" + pf.Node + "_" + pf.Service, ID: network + "/" + pf.UID})
+			}
+		}
+
+		var vobjects []tg.VNetObject
+		if x.get(ctx, base+"/network-object", &vobjects) {
+			for _, o := range vobjects {
+				objects = append(objects, object{Type: "tg_virtual_network_object", Name: network + "_" + o.Name, ID: network + "/" + o.Name})
+			}
+		}
+
+		var groups []tg.VNetGroup
+		if x.get(ctx, base+"/network-group", &groups) {
+			for _, g := range groups {
+				objects = append(objects, object{Type: "tg_virtual_network_group", Name: network + "_" + g.Name, ID: network + "/" + g.Name})
+
+				var members []tg.VNetGroupMembership
+				if x.get(ctx, base+"/network-group/"+g.Name, &members) {
+					for _, m := range members {
+						objects = append(objects, object{Type: "tg_virtual_network_group_membership", Name: network + "_" + g.Name + "_" + m.Object, ID: network + "/" + g.Name + "/" + m.Object})
+					}
+				}
+			}
+		}
+	}
+	return objects
+}
+
+func (x *discoverer) apps(ctx context.Context) []object {
+	var apps []listed
+	if !x.get(ctx, "/v2/application", &apps) {
+		return nil
+	}
+
+	var objects []object
+	for _, a := range apps {
+		objects = append(objects, object{Type: "tg_app", Name: a.Name, ID: a.key()})
+
+		var rules []listed
+		if x.get(ctx, "/v2/application/"+a.key()+"/access-rule", &rules) {
+			for _, r := range rules {
+				objects = append(objects, object{Type: "tg_app_access_rule", Name: a.Name + "_" + r.Name, ID: a.key() + "/" + r.key()})
+			}
+		}
+
+		var acls []listed
+		if x.get(ctx, "/v2/application/"+a.key()+"/acl", &acls) {
+			for _, acl := range acls {
+				objects = append(objects, object{Type: "tg_app_acl", Name: a.Name + "_" + acl.Description, ID: a.key() + "/" + acl.key()})
+			}
+		}
+	}
+	return objects
+}
+
+func (x *discoverer) idps(ctx context.Context) []object {
+	var idps []tg.IDP
+	if !x.get(ctx, "/v2/idp", &idps) {
+		return nil
+	}
+
+	var objects []object
+	for _, idp := range idps {
+		objects = append(objects, object{Type: "tg_idp", Name: idp.Name, ID: idp.UID})
+		switch idp.Type {
+		case "OpenID":
+			objects = append(objects, object{Type: "tg_idp_openid_config", Name: idp.Name, ID: idp.UID})
+		case "SAML":
+			objects = append(objects, object{Type: "tg_idp_saml_config", Name: idp.Name, ID: idp.UID})
+		}
+	}
+	return objects
+}
+
+func (x *discoverer) policies(ctx context.Context) []object {
+	var policies []tg.Policy
+	if !x.get(ctx, "/v2/policy", &policies) {
+		return nil
+	}
+
+	objects := make([]object, 0, len(policies))
+	for _, p := range policies {
+		objects = append(objects, object{Type: "tg_policy", Name: p.Name, ID: p.Name})
+	}
+	return objects
+}
+
+func (x *discoverer) users(ctx context.Context) []object {
+	var users []tg.User
+	if !x.get(ctx, "/user", &users) {
+		return nil
+	}
+
+	objects := make([]object, 0, len(users))
+	for _, u := range users {
+		objects = append(objects, object{Type: "tg_user", Name: u.Email, ID: u.Email})
+	}
+	return objects
+}
+
+func (x *discoverer) serviceUsers(ctx context.Context) []object {
+	var users []tg.ServiceUser
+	if !x.get(ctx, "/v2/service-user", &users) {
+		return nil
+	}
+
+	objects := make([]object, 0, len(users))
+	for _, u := range users {
+		objects = append(objects, object{Type: "tg_serviceuser", Name: u.Name, ID: u.Name})
+	}
+	return objects
+}
+
+func (x *discoverer) groups(ctx context.Context) []object {
+	var groups []tg.Group
+	if !x.get(ctx, "/v2/group", &groups) {
+		return nil
+	}
+
+	var objects []object
+	for _, g := range groups {
+		objects = append(objects, object{Type: "tg_group", Name: g.Name, ID: g.UID})
+
+		var members []tg.GroupMember
+		if x.get(ctx, "/v2/group/"+g.UID+"/members", &members) {
+			for _, m := range members {
+				objects = append(objects, object{Type: "tg_group_membership", Name: g.Name + "_" + m.User, ID: g.UID + "/" + m.User})
+			}
+		}
+	}
+	return objects
+}
+
+func (x *discoverer) alarms(ctx context.Context) []object {
+	var objects []object
+
+	var channels []tg.AlarmChannel
+	if x.get(ctx, "/v2/alarm-channel", &channels) {
+		for _, c := range channels {
+			objects = append(objects, object{Type: "tg_alarm_channel", Name: c.Name, ID: c.UID})
+		}
+	}
+
+	var alarms []tg.Alarm
+	if x.get(ctx, "/v2/alarm", &alarms) {
+		for _, a := range alarms {
+			objects = append(objects, object{Type: "tg_alarm", Name: a.Name, ID: a.UID})
+		}
+	}
+
+	return objects
+}
@@ -0,0 +1,132 @@
+// Package export writes the objects in an existing Trustgrid org out as Terraform configuration, so
+// the org can be brought under Terraform with `terraform plan` and Terraform 1.5 `import` blocks.
+package export
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+	"golang.org/x/sync/errgroup"
+)
+
+// importConcurrency bounds how many objects are read from the portal at once.
+const importConcurrency = 8
+
+// Run is the `export` command. It configures p the same way Terraform would, from the `TG_*`
+// environment variables and the shared credentials file, then writes the org to the `-out` directory.
+// Objects that can't be read are reported on stderr and left out.
+func Run(ctx context.Context, p *schema.Provider, args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	out := flags.String("out", ".", "directory to write the generated .tf files to")
+	profile := flags.String("profile", "", "profile in the shared credentials file to use")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := map[string]any{}
+	if *profile != "" {
+		config["profile"] = *profile
+	}
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		return fmt.Errorf("configuring provider: %s", diags[0].Summary)
+	}
+	tgc, ok := p.Meta().(*tg.Client)
+	if !ok {
+		return errors.New("provider did not configure a Trustgrid client")
+	}
+
+	files, err := Export(ctx, p.ResourcesMap, tgc, stderr)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(*out, name), files[name], 0o644); err != nil { //nolint: gosec // generated config is meant to be shared
+			return err
+		}
+	}
+	fmt.Fprintf(stderr, "wrote %d files to %s\n", len(names), *out)
+	return nil
+}
+
+// Export enumerates the org behind tgc, imports each object with its resource's importer and
+// renders the results as a map of file name to file contents. Objects that can't be listed or
+// imported are reported on warn and left out.
+func Export(ctx context.Context, resources map[string]*schema.Resource, tgc *tg.Client, warn io.Writer) (map[string][]byte, error) {
+	var mu sync.Mutex
+	warnf := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(warn, "warning: "+format+"\n", args...)
+	}
+
+	objects := (&discoverer{tgc: tgc, warnf: warnf}).discover(ctx)
+
+	states := make([]*schema.ResourceData, len(objects))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(importConcurrency)
+	for i, o := range objects {
+		r, ok := resources[o.Type]
+		if !ok || r.Importer == nil {
+			warnf("%s %q: resource does not support import", o.Type, o.ID)
+			continue
+		}
+		g.Go(func() error {
+			d, err := importObject(gctx, r, tgc, o.ID)
+			switch {
+			case gctx.Err() != nil:
+				return gctx.Err()
+			case err != nil:
+				warnf("%s %q: %s", o.Type, o.ID, err)
+			default:
+				states[i] = d
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var blocks []*block
+	for i, o := range objects {
+		if states[i] != nil {
+			blocks = append(blocks, &block{object: o, resource: resources[o.Type], data: states[i]})
+		}
+	}
+	return render(blocks)
+}
+
+// importObject runs the resource's importer the way `terraform import` would.
+func importObject(ctx context.Context, r *schema.Resource, tgc *tg.Client, id string) (*schema.ResourceData, error) {
+	d := r.Data(nil)
+	d.SetId(id)
+
+	states, err := r.Importer.StateContext(ctx, d, tgc)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(states) != 1:
+		return nil, fmt.Errorf("expected 1 imported object, got %d", len(states))
+	}
+	return states[0], nil
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/provider"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+const testNodeID = "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d"
+
+// fakePortal serves a small org: one node attached to one virtual network with a route, an
+// OpenID IDP, a group and an alarm with its channel. Everything else is a 404.
+func fakePortal(t *testing.T) *tg.Client {
+	t.Helper()
+
+	const vnet = "/api/v2/domain/example.trustgrid.io/network"
+	responses := map[string]string{
+		"/api/org/mine":                            `{"uid":"org-1","domain":"example.trustgrid.io"}`,
+		"/api/node":                                `[{"uid":"` + testNodeID + `","name":"edge1"}]`,
+		"/api/node/" + testNodeID:                  `{"uid":"` + testNodeID + `","name":"edge1","state":"ACTIVE"}`,
+		"/api/v2/node/" + testNodeID + "/vpn":      `[{"networkName":"corp","ip":"10.1.0.1"}]`,
+		"/api/v2/node/" + testNodeID + "/vpn/corp": `{"networkName":"corp","ip":"10.1.0.1"}`,
+		vnet:                       `[{"id":7,"name":"corp","networkCidr":"10.0.0.0/8","description":"Corporate"}]`,
+		vnet + "/corp/route":       `[{"uid":"r1","networkCidr":"10.1.0.0/24","nodeName":"edge1","metric":10}]`,
+		"/api/v2/idp":              `[{"uid":"i1","name":"Okta","class":"OpenID","description":"SSO"}]`,
+		"/api/v2/idp/i1":           `{"uid":"i1","name":"Okta","class":"OpenID","description":"SSO"}`,
+		"/api/v2/idp/openid/i1":    `{"issuer":"https://okta.example.com","clientId":"tg","authEndpoint":"https://okta.example.com/auth"}`,
+		"/api/v2/group":            `[{"uid":"g1","name":"Admins","idp":"i1"}]`,
+		"/api/v2/group/g1":         `{"uid":"g1","name":"Admins","idp":"i1"}`,
+		"/api/v2/group/g1/members": `[]`,
+		"/api/v2/alarm-channel":    `[{"uid":"c1","name":"Ops Pager","emails":"ops@example.com"}]`,
+		"/api/v2/alarm-channel/c1": `{"uid":"c1","name":"Ops Pager","emails":"ops@example.com"}`,
+		"/api/v2/alarm":            `[{"uid":"a1","name":"Node down","enabled":true,"channels":["c1"],"types":["Node Disconnect"]}]`,
+		"/api/v2/alarm/a1":         `{"uid":"a1","name":"Node down","enabled":true,"channels":["c1"],"types":["Node Disconnect"]}`,
+	}
+
+	mux := http.NewServeMux()
+	for path, body := range responses {
+		mux.HandleFunc("GET "+path, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, body)
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	tgc, err := tg.NewClient(context.Background(), tg.ClientParams{
+		APIKey:     "key",
+		APISecret:  "secret",
+		BaseURL:    srv.URL + "/api/",
+		HTTPClient: srv.Client(),
+	})
+	require.NoError(t, err)
+	return tgc
+}
+
+func TestExport(t *testing.T) {
+	tgc := fakePortal(t)
+	resources := provider.New("test")().ResourcesMap
+
+	var warnings bytes.Buffer
+	files, err := Export(t.Context(), resources, tgc, &warnings)
+	require.NoError(t, err)
+	assert.Empty(t, warnings.String())
+
+	assert.Equal(t, `import {
+  to = tg_virtual_network_route.corp_edge1_10_1_0_0_24
+  id = "corp/r1"
+}
+
+resource "tg_virtual_network_route" "corp_edge1_10_1_0_0_24" {
+  dest         = "edge1"
+  metric       = 10
+  network      = tg_virtual_network.corp.name
+  network_cidr = "10.1.0.0/24"
+}
+`, string(files["virtual_network_route.tf"]))
+
+	assert.Contains(t, string(files["virtual_network_attachment.tf"]), `node_id = tg_node_state.edge1.node_id`)
+	assert.Contains(t, string(files["virtual_network_attachment.tf"]), `network = tg_virtual_network.corp.name`)
+	assert.Contains(t, string(files["alarm.tf"]), `channels = [tg_alarm_channel.ops_pager.uid]`)
+	assert.NotRegexp(t, `(?m)^\s*uid\s*=`, string(files["alarm.tf"]), "portal-assigned IDs are left to Terraform")
+	assert.Contains(t, string(files["virtual_network.tf"]), `network_cidr = "10.0.0.0/8"`)
+	assert.Contains(t, string(files["idp_openid_config.tf"]), `idp_id             = tg_idp.okta.uid`)
+
+	// The portal doesn't return secrets, so they become variables.
+	assert.Contains(t, string(files["idp_openid_config.tf"]), `secret             = var.idp_openid_config_okta_secret`)
+	assert.Contains(t, string(files["variables.tf"]), `variable "idp_openid_config_okta_secret" {`)
+	assert.Contains(t, string(files["versions.tf"]), `source = "trustgrid/tg"`)
+
+	again, err := Export(t.Context(), resources, tgc, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, files, again)
+}
+
+func TestAssignLabels(t *testing.T) {
+	blocks := []*block{
+		{object: object{Type: "tg_user", Name: "b@example.com", ID: "b@example.com"}},
+		{object: object{Type: "tg_policy", Name: "Read Only", ID: "z"}},
+		{object: object{Type: "tg_policy", Name: "read-only", ID: "a"}},
+		{object: object{Type: "tg_policy", Name: "1st", ID: "m"}},
+		{object: object{Type: "tg_policy", Name: "!!", ID: "n"}},
+	}
+	assignLabels(blocks)
+
+	var labels []string
+	for _, b := range blocks {
+		labels = append(labels, b.Type+"."+b.label)
+	}
+	assert.Equal(t, []string{
+		"tg_policy._1st",
+		"tg_policy.read_only",
+		"tg_policy.read_only_2",
+		"tg_policy.unnamed",
+		"tg_user.b_example_com",
+	}, labels)
+	assert.Equal(t, "a", blocks[1].ID, "clashing names are numbered in import ID order")
+}
@@ -0,0 +1,372 @@
+package export
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// block is an imported object and the resource it is written out as.
+type block struct {
+	object
+	resource *schema.Resource
+	data     *schema.ResourceData
+	label    string
+}
+
+// reference is an attribute of another resource that a config value can point at instead of
+// repeating it, so Terraform knows the order to apply them in.
+type reference struct {
+	Type string
+	Attr string
+}
+
+// references maps attribute names to what their values refer to.
+var references = map[string]reference{
+	"app":               {Type: "tg_app", Attr: "uid"},
+	"channels":          {Type: "tg_alarm_channel", Attr: "uid"},
+	"cluster_fqdn":      {Type: "tg_cluster", Attr: "fqdn"},
+	"edge_node":         {Type: "tg_node_state", Attr: "node_id"},
+	"gateway_node":      {Type: "tg_node_state", Attr: "node_id"},
+	"group":             {Type: "tg_virtual_network_group", Attr: "name"},
+	"group_id":          {Type: "tg_group", Attr: "uid"},
+	"idp":               {Type: "tg_idp", Attr: "uid"},
+	"idp_id":            {Type: "tg_idp", Attr: "uid"},
+	"network":           {Type: "tg_virtual_network", Attr: "name"},
+	"network_name":      {Type: "tg_virtual_network", Attr: "name"},
+	"node_id":           {Type: "tg_node_state", Attr: "node_id"},
+	"object":            {Type: "tg_virtual_network_object", Attr: "name"},
+	"policy_ids":        {Type: "tg_policy", Attr: "name"},
+	"virtual_network":   {Type: "tg_virtual_network", Attr: "name"},
+	"visibility_groups": {Type: "tg_group", Attr: "uid"},
+}
+
+// targetKey is a referenced attribute value.
+type targetKey struct {
+	reference
+	Value string
+}
+
+// renderer turns imported state into configuration.
+type renderer struct {
+	// targets maps referenced values to the label of the resource holding them. Values held by more
+	// than one resource map to "" and are written out literally.
+	targets   map[targetKey]string
+	variables []string
+}
+
+// render writes blocks as one file per resource type, each resource preceded by its import block,
+// plus the variables for any sensitive values and the provider requirements.
+func render(blocks []*block) (map[string][]byte, error) {
+	assignLabels(blocks)
+
+	r := &renderer{targets: map[targetKey]string{}}
+	for _, b := range blocks {
+		r.addTargets(b)
+	}
+
+	files := map[string]*hclwrite.File{}
+	for _, b := range blocks {
+		name := strings.TrimPrefix(b.Type, "tg_") + ".tf"
+		f, ok := files[name]
+		if !ok {
+			f = hclwrite.NewEmptyFile()
+			files[name] = f
+		} else {
+			f.Body().AppendNewline()
+		}
+		if err := r.writeBlock(f.Body(), b); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", b.Type, b.label, err)
+		}
+	}
+
+	out := make(map[string][]byte, len(files)+2)
+	for name, f := range files {
+		out[name] = hclwrite.Format(f.Bytes())
+	}
+	out["versions.tf"] = versionsFile()
+	if len(r.variables) > 0 {
+		out["variables.tf"] = variablesFile(r.variables)
+	}
+	return out, nil
+}
+
+var labelSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sanitizeLabel turns name into a valid resource label.
+func sanitizeLabel(name string) string {
+	label := strings.Trim(labelSeparators.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	switch {
+	case label == "":
+		return "unnamed"
+	case label[0] >= '0' && label[0] <= '9':
+		return "_" + label
+	}
+	return label
+}
+
+// assignLabels sorts blocks by type and label and gives each a label unique within its type.
+// Clashing names are numbered in import ID order so repeated exports produce the same labels.
+func assignLabels(blocks []*block) {
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].Type != blocks[j].Type {
+			return blocks[i].Type < blocks[j].Type
+		}
+		return blocks[i].ID < blocks[j].ID
+	})
+
+	taken := map[string]bool{}
+	for _, b := range blocks {
+		base := sanitizeLabel(b.Name)
+		label := base
+		for n := 2; taken[b.Type+"."+label]; n++ {
+			label = fmt.Sprintf("%s_%d", base, n)
+		}
+		taken[b.Type+"."+label] = true
+		b.label = label
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].Type != blocks[j].Type {
+			return blocks[i].Type < blocks[j].Type
+		}
+		return blocks[i].label < blocks[j].label
+	})
+}
+
+// addTargets records the values of b that other resources may refer to.
+func (r *renderer) addTargets(b *block) {
+	added := map[targetKey]bool{}
+	for _, ref := range references {
+		if ref.Type != b.Type {
+			continue
+		}
+		value, ok := b.data.Get(ref.Attr).(string)
+		if !ok || value == "" {
+			continue
+		}
+		key := targetKey{reference: ref, Value: value}
+		if added[key] {
+			continue
+		}
+		added[key] = true
+		if _, seen := r.targets[key]; seen {
+			r.targets[key] = ""
+			continue
+		}
+		r.targets[key] = b.label
+	}
+}
+
+// target returns the traversal to the resource attribute holding value, if exactly one does.
+func (r *renderer) target(from *block, attr string, value any) (hcl.Traversal, bool) {
+	ref, ok := references[attr]
+	s, isString := value.(string)
+	if !ok || !isString || ref.Type == from.Type {
+		return nil, false
+	}
+	label := r.targets[targetKey{reference: ref, Value: s}]
+	if label == "" {
+		return nil, false
+	}
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: ref.Type},
+		hcl.TraverseAttr{Name: label},
+		hcl.TraverseAttr{Name: ref.Attr},
+	}, true
+}
+
+func (r *renderer) writeBlock(body *hclwrite.Body, b *block) error {
+	address := hcl.Traversal{hcl.TraverseRoot{Name: b.Type}, hcl.TraverseAttr{Name: b.label}}
+
+	imp := body.AppendNewBlock("import", nil).Body()
+	imp.SetAttributeTraversal("to", address)
+	imp.SetAttributeValue("id", cty.StringVal(b.ID))
+	body.AppendNewline()
+
+	res := body.AppendNewBlock("resource", []string{b.Type, b.label}).Body()
+	values := make(map[string]any, len(b.resource.Schema))
+	for k := range b.resource.Schema {
+		values[k] = b.data.Get(k)
+	}
+	return r.writeAttributes(res, b, b.resource.Schema, values, true)
+}
+
+// writeAttributes writes the configurable attributes in values, skipping any left at their default.
+func (r *renderer) writeAttributes(body *hclwrite.Body, b *block, sm map[string]*schema.Schema, values map[string]any, top bool) error {
+	keys := make([]string, 0, len(sm))
+	for k := range sm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := sm[k]
+		v := values[k]
+		switch {
+		case !s.Required && !s.Optional:
+			continue
+		case s.Required:
+		case isDefault(s, v):
+			continue
+		case top && s.Computed && v == b.data.Id():
+			// The portal-assigned ID, as with alarm UIDs; Terraform fills it in.
+			continue
+		}
+
+		if s.Sensitive && top && s.Type == schema.TypeString {
+			name := strings.TrimPrefix(b.Type, "tg_") + "_" + b.label + "_" + k
+			r.variables = append(r.variables, name)
+			body.SetAttributeTraversal(k, hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: name}})
+			continue
+		}
+
+		switch s.Type {
+		case schema.TypeList, schema.TypeSet:
+			items := listItems(v)
+			if elem, ok := s.Elem.(*schema.Resource); ok {
+				for _, item := range items {
+					m, _ := item.(map[string]any)
+					if err := r.writeAttributes(body.AppendNewBlock(k, nil).Body(), b, elem.Schema, m, false); err != nil {
+						return fmt.Errorf("%s: %w", k, err)
+					}
+				}
+				continue
+			}
+			tokens := make([]hclwrite.Tokens, 0, len(items))
+			for _, item := range items {
+				t, err := r.tokens(b, k, elemType(s), item)
+				if err != nil {
+					return fmt.Errorf("%s: %w", k, err)
+				}
+				tokens = append(tokens, t)
+			}
+			body.SetAttributeRaw(k, hclwrite.TokensForTuple(tokens))
+		case schema.TypeMap:
+			m, _ := v.(map[string]any)
+			attrs := make(map[string]cty.Value, len(m))
+			for mk, mv := range m {
+				val, err := primitive(elemType(s), mv)
+				if err != nil {
+					return fmt.Errorf("%s.%s: %w", k, mk, err)
+				}
+				attrs[mk] = val
+			}
+			body.SetAttributeValue(k, cty.ObjectVal(attrs))
+		default:
+			t, err := r.tokens(b, k, s.Type, v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			body.SetAttributeRaw(k, t)
+		}
+	}
+	return nil
+}
+
+// tokens renders a single value, as a reference to another resource where possible.
+func (r *renderer) tokens(b *block, attr string, t schema.ValueType, v any) (hclwrite.Tokens, error) {
+	if traversal, ok := r.target(b, attr, v); ok {
+		return hclwrite.TokensForTraversal(traversal), nil
+	}
+	val, err := primitive(t, v)
+	if err != nil {
+		return nil, err
+	}
+	return hclwrite.TokensForValue(val), nil
+}
+
+// elemType is the element type of a list, set or map of primitives. Maps without an element
+// schema hold strings.
+func elemType(s *schema.Schema) schema.ValueType {
+	if elem, ok := s.Elem.(*schema.Schema); ok {
+		return elem.Type
+	}
+	return schema.TypeString
+}
+
+func primitive(t schema.ValueType, v any) (cty.Value, error) {
+	switch t {
+	case schema.TypeString:
+		s, _ := v.(string)
+		return cty.StringVal(s), nil
+	case schema.TypeInt:
+		i, _ := v.(int)
+		return cty.NumberIntVal(int64(i)), nil
+	case schema.TypeFloat:
+		f, _ := v.(float64)
+		return cty.NumberVal(new(big.Float).SetFloat64(f)), nil
+	case schema.TypeBool:
+		b, _ := v.(bool)
+		return cty.BoolVal(b), nil
+	}
+	return cty.NilVal, fmt.Errorf("unsupported element type %s", t)
+}
+
+func listItems(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case *schema.Set:
+		return v.List()
+	}
+	return nil
+}
+
+// isDefault reports whether v is what the attribute would be if left out of the config.
+func isDefault(s *schema.Schema, v any) bool {
+	if s.Default != nil {
+		return reflect.DeepEqual(s.Default, v)
+	}
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case map[string]any:
+		return len(v) == 0
+	}
+	return len(listItems(v)) == 0
+}
+
+func versionsFile() []byte {
+	f := hclwrite.NewEmptyFile()
+	tf := f.Body().AppendNewBlock("terraform", nil).Body()
+	tf.SetAttributeValue("required_version", cty.StringVal(">= 1.5.0"))
+	providers := tf.AppendNewBlock("required_providers", nil).Body()
+	providers.SetAttributeValue("tg", cty.ObjectVal(map[string]cty.Value{
+		"source": cty.StringVal("trustgrid/tg"),
+	}))
+	return hclwrite.Format(f.Bytes())
+}
+
+// variablesFile declares the variables standing in for sensitive values, which the portal doesn't
+// return and so have to be supplied when planning.
+func variablesFile(names []string) []byte {
+	sort.Strings(names)
+
+	f := hclwrite.NewEmptyFile()
+	for i, name := range names {
+		if i > 0 {
+			f.Body().AppendNewline()
+		}
+		v := f.Body().AppendNewBlock("variable", []string{name}).Body()
+		v.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		v.SetAttributeValue("sensitive", cty.True)
+	}
+	return hclwrite.Format(f.Bytes())
+}
@@ -11,10 +11,10 @@ type App struct {
 	GatewayNodeID       string   `tf:"gateway_node"`
 	IDPID               string   `tf:"idp"`
 	IP                  string   `tf:"ip"`
-	Port                int      `tf:"port"`
+	Port                *int     `tf:"port"`
 	Protocol            string   `tf:"protocol"`
 	Hostname            string   `tf:"hostname"`
-	SessionDuration     int      `tf:"session_duration"`
+	SessionDuration     *int     `tf:"session_duration"`
 	TLSVerificationMode string   `tf:"tls_verification_mode"`
 	TrustMode           string   `tf:"trust_mode"`
 	GroupIDs            []string `tf:"visibility_groups"`
@@ -78,6 +78,7 @@ func (h *App) UpdateFromTG(a tg.App) {
 
 type AppACL struct {
 	AppID       string   `tf:"app"`
+	UID         string   `tf:"uid"`
 	Description string   `tf:"description"`
 	IPs         []string `tf:"ips"`
 	PortRange   string   `tf:"port_range"`
@@ -120,6 +121,7 @@ type AccessRuleItem struct {
 
 type AccessRule struct {
 	AppID      string           `tf:"app"`
+	UID        string           `tf:"uid"`
 	Action     string           `tf:"action"`
 	Name       string           `tf:"name"`
 	Exceptions []AccessRuleItem `tf:"exception"`
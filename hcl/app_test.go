@@ -0,0 +1,29 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_ExplicitZeroPayload(t *testing.T) {
+	d := configuredData(map[string]*schema.Schema{
+		"name":             {Type: schema.TypeString, Required: true},
+		"port":             {Type: schema.TypeInt, Optional: true},
+		"session_duration": {Type: schema.TypeInt, Optional: true},
+	}, map[string]string{"name": "wiki", "port": "0", "session_duration": "0"}, map[string]cty.Value{
+		"name":             cty.StringVal("wiki"),
+		"port":             cty.NullVal(cty.Number),
+		"session_duration": cty.Zero,
+	})
+
+	decoded, err := DecodeResourceData[App](d)
+	require.NoError(t, err)
+
+	js := payload(t, decoded.ToTG())
+	assert.Contains(t, js, `"sessionDuration":0`)
+	assert.NotContains(t, js, `"port"`)
+}
@@ -6,6 +6,7 @@ package hcl
 // struct so the resource Create/Update don't need manual d.Get(...).(string)
 // type assertions.
 type ClusterActiveMember struct {
-	ClusterFQDN string `tf:"cluster_fqdn"`
-	NodeID      string `tf:"node_id"`
+	ClusterFQDN   string `tf:"cluster_fqdn"`
+	NodeID        string `tf:"node_id"`
+	WaitForActive bool   `tf:"wait_for_active"`
 }
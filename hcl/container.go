@@ -10,95 +10,100 @@ import (
 )
 
 type ContainerImage struct {
-	Repository string `tf:"repository"`
-	Tag        string `tf:"tag"`
+	Repository string `tf:"repository" schema:"required" description:"Image repository"`
+	Tag        string `tf:"tag" schema:"required" description:"Image tag"`
 }
 
 type ContainerHealthCheck struct {
-	Command     string `tf:"command"`
-	Interval    int    `tf:"interval"`
-	Timeout     int    `tf:"timeout"`
-	StartPeriod int    `tf:"start_period"`
-	Retries     int    `tf:"retries"`
+	Command     string `tf:"command" schema:"required" description:"Command"`
+	Interval    int    `tf:"interval" schema:"required" description:"Interval"`
+	Timeout     int    `tf:"timeout" schema:"required" description:"Timeout"`
+	StartPeriod int    `tf:"start_period" schema:"required" description:"Grace period before health checks are monitored, in seconds"`
+	Retries     int    `tf:"retries" schema:"required" description:"Number of health checks that must fail before a container is considered unhealthy"`
 }
 
 type ContainerULimit struct {
-	Type string `tf:"type"`
-	Hard int    `tf:"hard"`
-	Soft int    `tf:"soft"`
+	Type string `tf:"type" schema:"required" description:"Limit type" validate:"oneof=core cpu data fsize locks memlock msgqueue nice nofile nproc rss rtprio rttime sigpending stack"`
+	Hard int    `tf:"hard" schema:"required" description:"Hard limit"`
+	Soft int    `tf:"soft" schema:"required" description:"Soft limit"`
 }
 
 type ContainerLimit struct {
-	CPUMax  int `tf:"cpu_max"`
-	IORBPS  int `tf:"io_rbps"`
-	IOWBPS  int `tf:"io_wbps"`
-	IORIOPS int `tf:"io_riops"`
-	IOWIOPS int `tf:"io_wiops"`
-	MemMax  int `tf:"mem_max"`
-	MemHigh int `tf:"mem_high"`
-
-	Limits []ContainerULimit `tf:"limits"`
+	CPUMax  *int `tf:"cpu_max" schema:"required" description:"CPU max allocation %"`
+	IORBPS  *int `tf:"io_rbps" schema:"optional" description:"Max allowed read throughput (bytes per second)"`
+	IOWBPS  *int `tf:"io_wbps" schema:"optional" description:"Max allowed write throughput (bytes per second)"`
+	IORIOPS *int `tf:"io_riops" schema:"optional" description:"Max allowed read throughput (IOPS)"`
+	IOWIOPS *int `tf:"io_wiops" schema:"optional" description:"Max allowed write throughput (IOPS)"`
+	MemMax  *int `tf:"mem_max" schema:"required" description:"Hard RAM allocation limit (MB)"`
+	MemHigh *int `tf:"mem_high" schema:"required" description:"Soft RAM allocation limit (MB)"`
+
+	Limits []ContainerULimit `tf:"limits" schema:"optional" description:"Linux kernel limits"`
 }
 
+// The descriptions of Type and Source quote values, which a struct tag can't, so the resource
+// overrides them.
 type ContainerMount struct {
-	UID    string `tf:"uid"`
+	UID    string `tf:"uid" schema:"computed" description:"Mount ID (for API use only)"`
 	Type   string `tf:"type"`
 	Source string `tf:"source"`
-	Dest   string `tf:"dest"`
+	Dest   string `tf:"dest" schema:"required" description:"Destination path in the container filesystem"`
 }
 
+// The description of Protocol quotes values, which a struct tag can't, so the resource overrides it.
 type ContainerPortMapping struct {
-	UID           string `tf:"uid"`
+	UID           string `tf:"uid" schema:"computed" description:"Port Mapping ID (for API use only)"`
 	Protocol      string `tf:"protocol"`
-	IFace         string `tf:"iface"`
-	HostPort      int    `tf:"host_port"`
-	ContainerPort int    `tf:"container_port"`
+	IFace         string `tf:"iface" schema:"required" description:"Host interface to expose port"`
+	HostPort      int    `tf:"host_port" schema:"required" description:"Host port"`
+	ContainerPort int    `tf:"container_port" schema:"required" description:"Container port"`
 }
 
 type ContainerVirtualNetwork struct {
-	UID           string `tf:"uid"`
-	Network       string `tf:"network"`
-	IP            string `tf:"ip"`
-	AllowOutbound bool   `tf:"allow_outbound"`
+	UID           string `tf:"uid" schema:"computed" description:"VNet ID (for API use only)"`
+	Network       string `tf:"network" schema:"required" description:"Virtual network name to attach - use the tg_virtual_network resource's exported name to help Terraform build a consistent dependency graph"`
+	IP            string `tf:"ip" schema:"required" description:"Virtual IP address"`
+	AllowOutbound bool   `tf:"allow_outbound" schema:"optional,default=true" description:"Allow outbound connections on this network"`
 }
 
 type ContainerInterface struct {
-	UID  string `tf:"uid"`
-	Name string `tf:"name"`
-	Dest string `tf:"dest"`
+	UID  string `tf:"uid" schema:"computed" description:"Interface ID (for API use only)"`
+	Name string `tf:"name" schema:"required" description:"Virtual interface name"`
+	Dest string `tf:"dest" schema:"required" description:"Internal interface destination IP" validate:"ipv4"`
 }
 
+// Container is a node or cluster container. AddCaps and DropCaps only accept long lists of
+// capabilities, so their schemas are spelled out by the resource.
 type Container struct {
-	NodeID              string           `tf:"node_id"`
-	ClusterFQDN         string           `tf:"cluster_fqdn"`
-	ID                  string           `tf:"id"`
-	Command             string           `tf:"command"`
-	Description         string           `tf:"description"`
-	Enabled             bool             `tf:"enabled"`
-	ExecType            string           `tf:"exec_type"`
-	Hostname            string           `tf:"hostname"`
-	Image               []ContainerImage `tf:"image"`
-	Name                string           `tf:"name"`
-	Privileged          bool             `tf:"privileged"`
-	RequireConnectivity bool             `tf:"require_connectivity"`
-	StopTime            int              `tf:"stop_time"`
-	UseInit             bool             `tf:"use_init"`
-	User                string           `tf:"user"`
-	VRF                 string           `tf:"vrf"`
+	NodeID              string           `tf:"node_id" schema:"optional,forcenew,exactlyoneof=node_id cluster_fqdn" description:"Node ID" validate:"uuid"`
+	ClusterFQDN         string           `tf:"cluster_fqdn" schema:"optional,forcenew,exactlyoneof=node_id cluster_fqdn" description:"Cluster FQDN" validate:"hostname"`
+	ID                  string           `tf:"id" schema:"computed" description:"Container ID"`
+	Command             string           `tf:"command" schema:"optional" description:"Command"`
+	Description         string           `tf:"description" schema:"optional" description:"Description"`
+	Enabled             bool             `tf:"enabled" schema:"optional,default=true" description:"Enabled"`
+	ExecType            string           `tf:"exec_type" schema:"required" description:"Container execution type - one of \"onDemand\", \"service\", or \"recurring\"" validate:"oneof=onDemand service recurring"`
+	Hostname            string           `tf:"hostname" schema:"optional" description:"Host name"`
+	Image               []ContainerImage `tf:"image" schema:"required,min=1,max=1"`
+	Name                string           `tf:"name" schema:"required" description:"Container Name"`
+	Privileged          bool             `tf:"privileged" schema:"optional,default=false" description:"Grant extended privileges to the container"`
+	RequireConnectivity bool             `tf:"require_connectivity" schema:"optional,default=false" description:"Ensures that a container that has encrypted volumes won't start unless the node has connectivity to the control plane"`
+	StopTime            int              `tf:"stop_time" schema:"optional,default=30" description:"Time to wait, in seconds, for container to stop gracefully"`
+	UseInit             bool             `tf:"use_init" schema:"optional,default=false" description:"Indicates that an init process should be used as PID 1 in the container. Ensures responsibilities of an init system are performed inside the container (i.e., handling exit signals)"`
+	User                string           `tf:"user" schema:"optional" description:"User"`
+	VRF                 string           `tf:"vrf" schema:"optional" description:"Container VRF"`
 
 	AddCaps      []string               `tf:"add_caps"`
 	DropCaps     []string               `tf:"drop_caps"`
-	Variables    map[string]string      `tf:"variables"`
-	Healthchecks []ContainerHealthCheck `tf:"healthcheck"`
+	Variables    map[string]string      `tf:"variables" schema:"optional" description:"Environment variables"`
+	Healthchecks []ContainerHealthCheck `tf:"healthcheck" schema:"optional,min=1,max=1"`
 
-	LogMaxFileSize int `tf:"log_max_file_size"`
-	LogMaxNumFiles int `tf:"log_max_num_files"`
+	LogMaxFileSize int `tf:"log_max_file_size" schema:"optional" description:"Maximum log file size (MB)"`
+	LogMaxNumFiles int `tf:"log_max_num_files" schema:"optional" description:"Maximum log files to keep"`
 
-	Limits          []ContainerLimit          `tf:"limits"`
-	Mounts          []ContainerMount          `tf:"mount"`
-	PortMappings    []ContainerPortMapping    `tf:"port_mapping"`
-	VirtualNetworks []ContainerVirtualNetwork `tf:"virtual_network"`
-	Interfaces      []ContainerInterface      `tf:"interface"`
+	Limits          []ContainerLimit          `tf:"limits" schema:"optional,min=1,max=1"`
+	Mounts          []ContainerMount          `tf:"mount" schema:"optional,computed"`
+	PortMappings    []ContainerPortMapping    `tf:"port_mapping" schema:"optional"`
+	VirtualNetworks []ContainerVirtualNetwork `tf:"virtual_network" schema:"optional"`
+	Interfaces      []ContainerInterface      `tf:"interface" schema:"optional"`
 }
 
 func (tfc *Container) ToTG() tg.Container {
@@ -234,16 +239,34 @@ func (tfc *Container) SetUIDs() {
 	}
 }
 
+// orderByUID sorts items into the order their UIDs already have in state. Items state doesn't
+// know about yet, such as everything on import, keep the portal's order after the known ones.
+func orderByUID[T any](items []T, known []string, uid func(T) string) []T {
+	existing := make(map[string]int, len(known))
+	for i, u := range known {
+		existing[u] = i
+	}
+
+	position := func(item T) int {
+		if i, ok := existing[uid(item)]; ok {
+			return i
+		}
+		return len(known)
+	}
+
+	return slices.SortedStableFunc(slices.Values(items), func(a, b T) int {
+		return position(a) - position(b)
+	})
+}
+
 func (tfc *Container) updateMounts(c tg.Container) {
-	existing := make(map[string]int)
-	for i := range tfc.Mounts {
-		existing[tfc.Mounts[i].UID] = i
+	known := make([]string, 0, len(tfc.Mounts))
+	for _, m := range tfc.Mounts {
+		known = append(known, m.UID)
 	}
 
 	tfc.Mounts = make([]ContainerMount, 0)
-	for _, m := range slices.SortedFunc(slices.Values(c.Config.Mounts), func(a, b tg.Mount) int {
-		return existing[a.UID] - existing[b.UID]
-	}) {
+	for _, m := range orderByUID(c.Config.Mounts, known, func(m tg.Mount) string { return m.UID }) {
 		tfc.Mounts = append(tfc.Mounts, ContainerMount{
 			UID:    m.UID,
 			Type:   m.Type,
@@ -254,15 +277,13 @@ func (tfc *Container) updateMounts(c tg.Container) {
 }
 
 func (tfc *Container) updateInterfaces(c tg.Container) {
-	existing := make(map[string]int)
-	for i := range tfc.Interfaces {
-		existing[tfc.Interfaces[i].UID] = i
+	known := make([]string, 0, len(tfc.Interfaces))
+	for _, i := range tfc.Interfaces {
+		known = append(known, i.UID)
 	}
 
 	tfc.Interfaces = make([]ContainerInterface, 0)
-	for _, i := range slices.SortedFunc(slices.Values(c.Config.Interfaces), func(a, b tg.ContainerInterface) int {
-		return existing[a.UID] - existing[b.UID]
-	}) {
+	for _, i := range orderByUID(c.Config.Interfaces, known, func(i tg.ContainerInterface) string { return i.UID }) {
 		tfc.Interfaces = append(tfc.Interfaces, ContainerInterface{
 			UID:  i.UID,
 			Name: i.Name,
@@ -272,15 +293,13 @@ func (tfc *Container) updateInterfaces(c tg.Container) {
 }
 
 func (tfc *Container) updateVirtualNetworks(c tg.Container) {
-	existing := make(map[string]int)
-	for i := range tfc.VirtualNetworks {
-		existing[tfc.VirtualNetworks[i].UID] = i
+	known := make([]string, 0, len(tfc.VirtualNetworks))
+	for _, vnet := range tfc.VirtualNetworks {
+		known = append(known, vnet.UID)
 	}
 
 	tfc.VirtualNetworks = make([]ContainerVirtualNetwork, 0)
-	for _, vnet := range slices.SortedFunc(slices.Values(c.Config.VirtualNetworks), func(a, b tg.ContainerVirtualNetwork) int {
-		return existing[a.UID] - existing[b.UID]
-	}) {
+	for _, vnet := range orderByUID(c.Config.VirtualNetworks, known, func(v tg.ContainerVirtualNetwork) string { return v.UID }) {
 		tfc.VirtualNetworks = append(tfc.VirtualNetworks, ContainerVirtualNetwork{
 			UID:           vnet.UID,
 			Network:       vnet.Network,
@@ -291,15 +310,13 @@ func (tfc *Container) updateVirtualNetworks(c tg.Container) {
 }
 
 func (tfc *Container) updatePortMappings(c tg.Container) {
-	existing := make(map[string]int)
-	for i := range tfc.PortMappings {
-		existing[tfc.PortMappings[i].UID] = i
+	known := make([]string, 0, len(tfc.PortMappings))
+	for _, pm := range tfc.PortMappings {
+		known = append(known, pm.UID)
 	}
 
 	tfc.PortMappings = make([]ContainerPortMapping, 0)
-	for _, pm := range slices.SortedFunc(slices.Values(c.Config.PortMappings), func(a, b tg.PortMapping) int {
-		return existing[a.UID] - existing[b.UID]
-	}) {
+	for _, pm := range orderByUID(c.Config.PortMappings, known, func(pm tg.PortMapping) string { return pm.UID }) {
 		tfc.PortMappings = append(tfc.PortMappings, ContainerPortMapping{
 			UID:           pm.UID,
 			Protocol:      pm.Protocol,
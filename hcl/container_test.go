@@ -0,0 +1,118 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// portalContainer is a container as the portal returns it, with the UIDs the portal UI generates
+// rather than the ones SetUIDs would.
+func portalContainer() tg.Container {
+	c := tg.Container{
+		NodeID:      "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d",
+		ID:          "c1",
+		Name:        "web",
+		Enabled:     true,
+		ExecType:    "service",
+		StopTime:    30,
+		Description: "web server",
+	}
+	c.Image.Repository = "nginx"
+	c.Image.Tag = "1.27"
+
+	cc := &c.Config
+	cc.VRF = &tg.ContainerVRF{Name: "default"}
+	cc.Capabilities.AddCaps = []string{"NET_ADMIN"}
+	cc.Variables = []tg.ContainerVar{{Name: "MODE", Value: "prod"}}
+	cc.Logging.MaxFileSize = 10
+	cc.Logging.NumFiles = 2
+	cc.HealthCheck = &tg.HealthCheck{Command: "curl localhost", Interval: 10, Timeout: 5, StartPeriod: 30, Retries: 3}
+	cc.Limits = &tg.ContainerLimits{CPUMax: ptr(50), MemMax: ptr(512), Limits: []tg.ULimit{{Type: "nofile", Hard: 1024, Soft: 512}}}
+	cc.Mounts = []tg.Mount{
+		{UID: "m-z", Type: "volume", Source: "data", Dest: "/data"},
+		{UID: "m-a", Type: "bind", Source: "/etc/ssl", Dest: "/ssl"},
+		{UID: "m-k", Type: "volume", Source: "logs", Dest: "/var/log"},
+	}
+	cc.PortMappings = []tg.PortMapping{
+		{UID: "p-2", Protocol: "tcp", IFace: "ens160", HostPort: 8443, ContainerPort: 443},
+		{UID: "p-1", Protocol: "tcp", IFace: "ens160", HostPort: 8080, ContainerPort: 80},
+	}
+	cc.VirtualNetworks = []tg.ContainerVirtualNetwork{{UID: "v-1", Network: "vnet1", IP: "10.0.0.5", AllowOutbound: true}}
+	cc.Interfaces = []tg.ContainerInterface{{UID: "i-1", Name: "eth1", Dest: "10.1.0.0/24"}}
+	return c
+}
+
+func Test_Container_ImportRoundTrip(t *testing.T) {
+	ct := portalContainer()
+
+	imported := Container{}
+	imported.UpdateFromTG(ct)
+
+	require.Len(t, imported.Mounts, 3)
+	assert.Equal(t, []string{"m-z", "m-a", "m-k"}, []string{imported.Mounts[0].UID, imported.Mounts[1].UID, imported.Mounts[2].UID})
+	assert.Equal(t, "p-2", imported.PortMappings[0].UID)
+	assert.Equal(t, map[string]string{"MODE": "prod"}, imported.Variables)
+	assert.Equal(t, "default", imported.VRF)
+
+	refreshed := imported
+	refreshed.UpdateFromTG(ct)
+	assert.Equal(t, imported, refreshed)
+
+	written := imported.ToTG()
+	assert.Equal(t, ct, written)
+}
+
+func Test_Container_UpdateFromTG_KeepsStateOrder(t *testing.T) {
+	ct := portalContainer()
+
+	tf := Container{Mounts: []ContainerMount{{UID: "m-k"}, {UID: "m-z"}}}
+	tf.UpdateFromTG(ct)
+
+	assert.Equal(t, []string{"m-k", "m-z", "m-a"}, []string{tf.Mounts[0].UID, tf.Mounts[1].UID, tf.Mounts[2].UID})
+}
+
+func Test_Container_ExplicitZeroLimitsPayload(t *testing.T) {
+	d := configuredData(Schema[Container](nil), map[string]string{
+		"node_id":            "node1",
+		"name":               "web",
+		"image.#":            "1",
+		"image.0.repository": "nginx",
+		"image.0.tag":        "1.27",
+		"limits.#":           "1",
+		"limits.0.cpu_max":   "50",
+		"limits.0.mem_max":   "512",
+		"limits.0.mem_high":  "256",
+		"limits.0.io_rbps":   "0",
+		"limits.0.io_wbps":   "0",
+	}, map[string]cty.Value{
+		"node_id": cty.StringVal("node1"),
+		"name":    cty.StringVal("web"),
+		"image": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"repository": cty.StringVal("nginx"),
+			"tag":        cty.StringVal("1.27"),
+		})}),
+		"limits": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"cpu_max":  cty.NumberIntVal(50),
+			"mem_max":  cty.NumberIntVal(512),
+			"mem_high": cty.NumberIntVal(256),
+			"io_rbps":  cty.Zero,
+			"io_wbps":  cty.NullVal(cty.Number),
+		})}),
+	})
+
+	decoded, err := DecodeResourceData[Container](d)
+	require.NoError(t, err)
+
+	js := payload(t, decoded.ToTG().Config.Limits)
+	assert.Contains(t, js, `"cpuMax":50`)
+	assert.Contains(t, js, `"ioRbps":0`)
+	assert.NotContains(t, js, `"ioWbps"`)
+}
@@ -0,0 +1,162 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// AttributePath parses an attribute address like `vrf.0.acl.2.source`, as used in encoder errors,
+// into a path. Numeric steps index into lists.
+func AttributePath(addr string) cty.Path {
+	var path cty.Path
+	for _, step := range strings.Split(addr, ".") {
+		if i, err := strconv.Atoi(step); err == nil {
+			path = path.IndexInt(i)
+		} else if step != "" {
+			path = path.GetAttr(step)
+		}
+	}
+	return path
+}
+
+// AttributeError is an error diagnostic Terraform shows against the attribute at path.
+func AttributeError(path cty.Path, summary, detail string) diag.Diagnostic {
+	return diag.Diagnostic{Severity: diag.Error, Summary: summary, Detail: detail, AttributePath: path}
+}
+
+// AttributeWarning is a warning diagnostic Terraform shows against the attribute at path.
+func AttributeWarning(path cty.Path, summary, detail string) diag.Diagnostic {
+	return diag.Diagnostic{Severity: diag.Warning, Summary: summary, Detail: detail, AttributePath: path}
+}
+
+// PathError ties err to the attribute at addr. Returned as is from a CustomizeDiff function, or
+// passed to `Diagnostics`, it makes Terraform point at the attribute. The SDK only recognizes it
+// unwrapped, so it shouldn't be wrapped with `fmt.Errorf`.
+func PathError(addr string, err error) error {
+	return AttributePath(addr).NewError(err)
+}
+
+// Diagnostics converts err into diagnostics pointing at the attributes of T responsible for it,
+// where they can be told:
+//
+//   - errors made with `PathError` point at their attribute.
+//   - portal validation failures (422s) that name fields of the request body, which was an A, get
+//     a diagnostic per field. Fields are matched to T's attributes by Go field name, since the
+//     `hcl` models mirror the `tg` ones, as far down the path as they match.
+//
+// Other API errors are summarized by the portal's message with the full error as detail, and
+// anything else is reported like `diag.FromErr`.
+func Diagnostics[T any, A any](err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	var pathErr cty.PathError
+	if errors.As(err, &pathErr) {
+		return diag.Diagnostics{AttributeError(pathErr.Path, err.Error(), "")}
+	}
+
+	var apiErr *tg.APIError
+	if !errors.As(err, &apiErr) {
+		return diag.FromErr(err)
+	}
+
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || len(apiErr.Fields) == 0 {
+		summary := apiErr.Message
+		if summary == "" {
+			summary = http.StatusText(apiErr.StatusCode)
+		}
+		return diag.Diagnostics{{Severity: diag.Error, Summary: summary, Detail: err.Error()}}
+	}
+
+	var diags diag.Diagnostics
+	for _, f := range apiErr.Fields {
+		path := portalPath(f.Field, reflect.TypeFor[A](), reflect.TypeFor[T]())
+		detail := fmt.Sprintf("The portal rejected %s: %s", f.Field, err)
+		diags = append(diags, AttributeError(path, f.Message, detail))
+	}
+	return diags
+}
+
+// portalPath maps field, a path into a request body of type body like `vrfs[0].acls[2].source`, to
+// the path of the matching attribute of model. It stops at the deepest attribute it can match.
+func portalPath(field string, body reflect.Type, model reflect.Type) cty.Path {
+	steps := strings.FieldsFunc(field, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']' || r == '/'
+	})
+
+	var path cty.Path
+	for _, step := range steps {
+		body, model = deref(body), deref(model)
+
+		if i, err := strconv.Atoi(step); err == nil {
+			if (body.Kind() != reflect.Slice && body.Kind() != reflect.Array) || model.Kind() != reflect.Slice {
+				return path
+			}
+			path = path.IndexInt(i)
+			body, model = body.Elem(), model.Elem()
+			continue
+		}
+
+		if body.Kind() != reflect.Struct {
+			return path
+		}
+		bodyField, ok := jsonField(body, step)
+		if !ok {
+			return path
+		}
+
+		// A single block in the request is the only item of a list in the model.
+		if model.Kind() == reflect.Slice && deref(model.Elem()).Kind() == reflect.Struct {
+			path = path.IndexInt(0)
+			model = deref(model.Elem())
+		}
+		if model.Kind() != reflect.Struct {
+			return path
+		}
+		modelField, ok := model.FieldByName(bodyField.Name)
+		if !ok {
+			return path
+		}
+		name, _ := tfName(modelField)
+		if name == "" || name == "-" {
+			return path
+		}
+
+		path = path.GetAttr(name)
+		body, model = bodyField.Type, modelField.Type
+
+		// So is a struct, as `EncodeResourceData` has it.
+		if deref(model).Kind() == reflect.Struct {
+			path = path.IndexInt(0)
+		}
+	}
+	return path
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// jsonField finds the field of t that encodes as name in JSON.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	return t.FieldByNameFunc(func(fieldName string) bool {
+		field, _ := t.FieldByName(fieldName)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "" {
+			return strings.EqualFold(fieldName, name)
+		}
+		return tag == name
+	})
+}
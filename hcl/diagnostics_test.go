@@ -0,0 +1,102 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func TestAttributePath(t *testing.T) {
+	assert.Equal(t, cty.GetAttrPath("vrf").IndexInt(0).GetAttr("acl").IndexInt(2).GetAttr("source"), AttributePath("vrf.0.acl.2.source"))
+	assert.Equal(t, cty.GetAttrPath("name"), AttributePath("name"))
+	assert.Empty(t, AttributePath(""))
+}
+
+func TestDiagnostics_PathError(t *testing.T) {
+	err := PathError("interface.1.dhcp", errors.New("dhcp isn't allowed on clusters"))
+	assert.EqualError(t, err, "dhcp isn't allowed on clusters")
+
+	diags := Diagnostics[NetworkConfig, tg.NetworkConfig](err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Error, diags[0].Severity)
+	assert.Equal(t, "dhcp isn't allowed on clusters", diags[0].Summary)
+	assert.Equal(t, AttributePath("interface.1.dhcp"), diags[0].AttributePath)
+}
+
+func TestDiagnostics_FieldErrors(t *testing.T) {
+	err := fmt.Errorf("saving: %w", &tg.APIError{
+		Method:     http.MethodPut,
+		URL:        "/node/abc/config/network",
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "invalid cidr; unknown nic",
+		Fields: []tg.FieldError{
+			{Field: "vrfs[0].acls[2].source", Message: "invalid cidr"},
+			{Field: "interfaces.1.routes.0.route", Message: "unknown nic"},
+			{Field: "interfaces[1].bogus", Message: "what"},
+			{Field: "nonsense", Message: "huh"},
+		},
+	})
+
+	diags := Diagnostics[NetworkConfig, tg.NetworkConfig](err)
+	require.Len(t, diags, 4)
+
+	assert.Equal(t, "invalid cidr", diags[0].Summary)
+	assert.Equal(t, AttributePath("vrf.0.acl.2.source"), diags[0].AttributePath)
+	assert.Contains(t, diags[0].Detail, "The portal rejected vrfs[0].acls[2].source")
+	assert.Contains(t, diags[0].Detail, "/node/abc/config/network")
+
+	assert.Equal(t, AttributePath("interface.1.route.0.route"), diags[1].AttributePath)
+	assert.Equal(t, AttributePath("interface.1"), diags[2].AttributePath, "stops at the deepest match")
+	assert.Empty(t, diags[3].AttributePath)
+}
+
+func TestDiagnostics_SingleBlocks(t *testing.T) {
+	err := &tg.APIError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Fields: []tg.FieldError{
+			{Field: "image.tag", Message: "required"},
+			{Field: "limits.limits[1].soft", Message: "too high"},
+			{Field: "mounts[0].mountType", Message: "bad type"},
+		},
+	}
+
+	diags := Diagnostics[Container, tg.Container](err)
+	require.Len(t, diags, 3)
+	assert.Equal(t, AttributePath("image.0.tag"), diags[0].AttributePath)
+
+	diags = Diagnostics[Container, tg.ContainerConfig](err)
+	assert.Equal(t, AttributePath("limits.0.limits.1.soft"), diags[1].AttributePath)
+	assert.Equal(t, AttributePath("mount.0.type"), diags[2].AttributePath)
+}
+
+func TestDiagnostics_OtherErrors(t *testing.T) {
+	assert.Nil(t, Diagnostics[NetworkConfig, tg.NetworkConfig](nil))
+
+	diags := Diagnostics[NetworkConfig, tg.NetworkConfig](errors.New("boom"))
+	require.Len(t, diags, 1)
+	assert.Equal(t, "boom", diags[0].Summary)
+	assert.Empty(t, diags[0].AttributePath)
+
+	err := fmt.Errorf("cannot lookup cluster: %w", &tg.APIError{
+		Method:     http.MethodGet,
+		URL:        "/cluster/edge.example.com",
+		StatusCode: http.StatusForbidden,
+	})
+	diags = Diagnostics[NetworkConfig, tg.NetworkConfig](err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Forbidden", diags[0].Summary)
+	assert.Equal(t, err.Error(), diags[0].Detail)
+}
+
+func TestAttributeWarning(t *testing.T) {
+	d := AttributeWarning(AttributePath("add_caps"), "Redundant", "privileged containers have every capability")
+	assert.Equal(t, diag.Warning, d.Severity)
+	assert.Equal(t, cty.GetAttrPath("add_caps"), d.AttributePath)
+}
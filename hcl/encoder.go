@@ -2,9 +2,13 @@ package hcl
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/mitchellh/mapstructure"
 )
@@ -13,28 +17,41 @@ type tfGetter interface {
 	GetOk(string) (any, bool)
 }
 
+// rawConfigGetter is implemented by both ResourceData and ResourceDiff. The raw config is null
+// outside of plan and apply, like during refresh and import.
+type rawConfigGetter interface {
+	GetRawConfig() cty.Value
+}
+
 func decodeTFTagged[T any](d tfGetter) (T, error) {
 	fields := make(map[string]any)
 
-	target := new(T)
-	for i := 0; i < reflect.TypeOf(target).Elem().NumField(); i++ {
-		field := reflect.TypeOf(target).Elem().FieldByIndex([]int{i})
-		tf := field.Tag.Get("tf")
-		if tf == "" {
-			continue
-		}
+	var config cty.Value
+	if g, ok := d.(rawConfigGetter); ok {
+		config = g.GetRawConfig()
+	}
 
-		vals := strings.Split(tf, ",")
-		tf = vals[0]
+	target := new(T)
+	for _, tf := range tfNames(reflect.TypeFor[T]()) {
 		v, ok := d.GetOk(tf)
-		if ok {
+		raw, known := configAttr(config, tf)
+		switch {
+		case ok && known:
+			fields[tf] = pruneNulls(v, raw)
+		case ok:
+			fields[tf] = v
+		case known && !raw.IsNull():
+			// GetOk reports zero values as unset, but the config has it, so this is an explicit
+			// `false`, `0` or `""` that pointer fields should see.
 			fields[tf] = v
 		}
 	}
 
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		TagName: "tf",
-		Result:  target,
+		TagName:    "tf",
+		Result:     target,
+		Squash:     true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(setToList, singleBlock),
 	})
 	if err != nil {
 		return *target, err
@@ -43,8 +60,47 @@ func decodeTFTagged[T any](d tfGetter) (T, error) {
 	return *target, decoder.Decode(fields)
 }
 
+// setToList decodes sets as lists, in whatever order the SDK keeps them.
+func setToList(_ reflect.Type, _ reflect.Type, data any) (any, error) {
+	if set, ok := data.(*schema.Set); ok {
+		return set.List(), nil
+	}
+	return data, nil
+}
+
+// singleBlock decodes a block of at most one item into a struct, or a pointer to one that is
+// left nil when the block is empty.
+func singleBlock(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	items, ok := data.([]any)
+	if !ok {
+		return data, nil
+	}
+	isPointer := to.Kind() == reflect.Pointer
+	if isPointer {
+		to = to.Elem()
+	}
+	if to.Kind() != reflect.Struct {
+		return data, nil
+	}
+
+	switch len(items) {
+	case 0:
+		if isPointer {
+			return nil, nil
+		}
+		return map[string]any{}, nil
+	case 1:
+		return items[0], nil
+	}
+	return nil, fmt.Errorf("expected a single block, got %d", len(items))
+}
+
 // DecodeResourceData decodes TF resource data (HCL+schema filters/etc) into the given struct,
-// using the `tf` tag. If a field doesn't have a `tf` tag, it won't be populated.
+// using the `tf` tag. If a field doesn't have a `tf` tag, it won't be populated. It supports
+// the same shapes as `EncodeResourceData`.
+// Pointer fields are nil when the attribute is left out of the config, and point at the zero
+// value when it's explicitly set to one. Outside of plan and apply there's no config to go by,
+// so zero values are treated as unset.
 func DecodeResourceData[T any](d *schema.ResourceData) (T, error) {
 	return decodeTFTagged[T](d)
 }
@@ -55,53 +111,214 @@ func DecodeResourceDiff[T any](d *schema.ResourceDiff) (T, error) {
 	return decodeTFTagged[T](d)
 }
 
-func convertToMap(in any) (map[string]any, error) {
-	out := make(map[string]any)
+// configAttr returns the raw config value of the top-level attribute name. It returns false when
+// there's no config to go by or the value isn't known yet.
+func configAttr(config cty.Value, name string) (cty.Value, bool) {
+	if config == cty.NilVal || config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(name) {
+		return cty.NilVal, false
+	}
+	v := config.GetAttr(name)
+	return v, v.IsKnown()
+}
 
-	for i := 0; i < reflect.TypeOf(in).NumField(); i++ {
-		field := reflect.TypeOf(in).FieldByIndex([]int{i})
-		tf := field.Tag.Get("tf")
-		if tf == "" || tf == "-" {
-			continue
+// pruneNulls drops zero values from the nested blocks in v that are null in raw, its config, so
+// pointer fields in nested structs stay nil when left out rather than pointing at a zero value.
+// Non-zero values, from defaults or computed attributes, are kept. Sets are left alone since
+// their order doesn't match the config's.
+func pruneNulls(v any, raw cty.Value) any {
+	if raw.IsNull() || !raw.IsKnown() {
+		return v
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		if !raw.Type().IsObjectType() {
+			return v
 		}
-		vals := strings.Split(tf, ",")
-		tf = vals[0]
-
-		//exhaustive:ignore
-		switch field.Type.Kind() {
-		case reflect.Slice:
-			slice := make([]any, 0)
-			for j := 0; j < reflect.ValueOf(in).FieldByIndex([]int{i}).Len(); j++ {
-				el := reflect.ValueOf(in).FieldByIndex([]int{i}).Index(j)
-				//exhaustive:ignore
-				switch el.Kind() {
-				case reflect.Struct:
-					e, err := convertToMap(reflect.ValueOf(in).FieldByIndex([]int{i}).Index(j).Interface())
-					if err != nil {
-						return out, fmt.Errorf("error converting slice element %d: %w", j, err)
-					}
-					slice = append(slice, e)
-				default:
-					slice = append(slice, el.Interface())
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			if raw.Type().HasAttribute(k) {
+				attr := raw.GetAttr(k)
+				if attr.IsKnown() && attr.IsNull() && (item == nil || reflect.ValueOf(item).IsZero()) {
+					continue
 				}
+				item = pruneNulls(item, attr)
 			}
-			out[tf] = slice
-		case reflect.Pointer:
-			value := reflect.ValueOf(in).FieldByIndex([]int{i})
-			if value.IsNil() {
-				continue
+			out[k] = item
+		}
+		return out
+	case []any:
+		if (!raw.Type().IsListType() && !raw.Type().IsTupleType()) || raw.LengthInt() != len(v) {
+			return v
+		}
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = pruneNulls(item, raw.Index(cty.NumberIntVal(int64(i))))
+		}
+		return out
+	}
+	return v
+}
+
+func convertToMap(in any) (map[string]any, error) {
+	v := reflect.ValueOf(in)
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %T", in)
+	}
+	return encodeStruct("", v)
+}
+
+// tfName returns the attribute name in a field's `tf` tag and whether it has `omitempty`.
+func tfName(field reflect.StructField) (string, bool) {
+	name, opts, _ := strings.Cut(field.Tag.Get("tf"), ",")
+	return name, slices.Contains(strings.Split(opts, ","), "omitempty")
+}
+
+// embedded reports whether field is an embedded struct whose fields are promoted into its parent's
+// attributes, which is the case when it has no `tf` tag of its own.
+func embedded(field reflect.StructField) bool {
+	return field.Anonymous && field.Tag.Get("tf") == "" && field.Type.Kind() == reflect.Struct
+}
+
+// tfNames lists the attribute names of the `tf` tagged fields of t, including those of embedded
+// structs.
+func tfNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if embedded(field) {
+			names = append(names, tfNames(field.Type)...)
+			continue
+		}
+		if name, _ := tfName(field); name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func attrPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// encodeStruct converts v to the attribute map the SDK expects for a block. Nil pointers are left
+// out, and `omitempty` fields with a zero value are encoded as null so they're cleared rather
+// than set to the zero value.
+func encodeStruct(path string, v reflect.Value) (map[string]any, error) {
+	out := make(map[string]any)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if embedded(field) {
+			promoted, err := encodeStruct(path, v.Field(i))
+			if err != nil {
+				return nil, err
 			}
-			out[tf] = value.Elem().Interface()
+			maps.Copy(out, promoted)
+			continue
+		}
+
+		name, omitempty := tfName(field)
+		if name == "" || name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		fpath := attrPath(path, name)
+
+		switch {
+		case omitempty && fv.IsZero():
+			out[name] = nil
+		case fv.Kind() == reflect.Pointer && fv.IsNil():
+			continue
 		default:
-			out[tf] = reflect.ValueOf(in).FieldByIndex([]int{i}).Interface()
+			value, err := encodeField(fpath, fv)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = value
 		}
 	}
 
 	return out, nil
 }
 
+// encodeField encodes a struct field, which is a single block if it's a struct or a pointer to one.
+func encodeField(path string, v reflect.Value) (any, error) {
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return encodeValue(path, v)
+	}
+
+	block, err := encodeStruct(path+".0", v)
+	if err != nil {
+		return nil, err
+	}
+	return []any{block}, nil
+}
+
+// encodeValue converts v to the primitives, `[]any` and `map[string]any` the SDK's `Set` expects.
+// Named types are converted to their underlying primitive, and sets are encoded as lists, which
+// the SDK hashes according to the schema.
+func encodeValue(path string, v reflect.Value) (any, error) {
+	//exhaustive:ignore
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(path, v.Elem())
+	case reflect.Struct:
+		return encodeStruct(path, v)
+	case reflect.Slice, reflect.Array:
+		out := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := encodeValue(attrPath(path, strconv.Itoa(i)), v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, item)
+		}
+		return out, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%s: map keys must be strings, got %s", path, v.Type().Key())
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			item, err := encodeValue(attrPath(path, key), iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			out[key] = item
+		}
+		return out, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), nil //nolint: gosec // attribute values are nowhere near overflowing
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+	return nil, fmt.Errorf("%s: unsupported type %s", path, v.Type())
+}
+
 // EncodeResourceData sets the values on the given ResourceData according to the struct's
-// tf tags.
+// tf tags. Structs and pointers to structs are set as blocks of one item, slices as lists or
+// sets depending on the schema, and the fields of embedded structs as the parent's own. Errors
+// name the offending attribute, like `interface.0.route.1.dest`.
 func EncodeResourceData(in any, d *schema.ResourceData) error {
 	var out map[string]any
 	var err error
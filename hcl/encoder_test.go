@@ -1,10 +1,17 @@
 package hcl
 
 import (
+	"encoding/json"
+	"math/rand/v2"
+	"reflect"
+	"slices"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type fakeGetter map[string]any
@@ -229,6 +236,142 @@ func TestDecodeTFTagged_Structs(t *testing.T) {
 	assert.True(t, decoded.Nested[0].Bool)
 }
 
+// fakeConfigGetter is a fakeGetter that also has the raw config, as during plan and apply. Like
+// ResourceData, it reports zero values as unset.
+type fakeConfigGetter struct {
+	fakeGetter
+	config cty.Value
+}
+
+func (f fakeConfigGetter) GetOk(key string) (any, bool) {
+	value, ok := f.fakeGetter[key]
+	return value, ok && !reflect.ValueOf(value).IsZero()
+}
+
+func (f fakeConfigGetter) GetRawConfig() cty.Value {
+	return f.config
+}
+
+func TestDecode_ExplicitZero(t *testing.T) {
+	type config struct {
+		Enabled     *bool   `tf:"enabled"`
+		Metric      *int    `tf:"metric"`
+		Description *string `tf:"description"`
+		Unset       *bool   `tf:"unset"`
+		Plain       bool    `tf:"plain"`
+	}
+
+	values := fakeGetter{"enabled": false, "metric": 0, "description": "", "unset": false, "plain": false}
+
+	decoded, err := decodeTFTagged[config](fakeConfigGetter{fakeGetter: values, config: cty.ObjectVal(map[string]cty.Value{
+		"enabled":     cty.False,
+		"metric":      cty.Zero,
+		"description": cty.StringVal(""),
+		"unset":       cty.NullVal(cty.Bool),
+		"plain":       cty.False,
+	})})
+	require.NoError(t, err)
+	require.NotNil(t, decoded.Enabled)
+	assert.False(t, *decoded.Enabled)
+	require.NotNil(t, decoded.Metric)
+	assert.Equal(t, 0, *decoded.Metric)
+	require.NotNil(t, decoded.Description)
+	assert.Empty(t, *decoded.Description)
+	assert.Nil(t, decoded.Unset)
+	assert.False(t, decoded.Plain)
+
+	// Without a config, as during refresh, zero values still can't be told from unset.
+	decoded, err = decodeTFTagged[config](fakeConfigGetter{fakeGetter: values, config: cty.NullVal(cty.EmptyObject)})
+	require.NoError(t, err)
+	assert.Nil(t, decoded.Enabled)
+	assert.Nil(t, decoded.Metric)
+	assert.Nil(t, decoded.Description)
+}
+
+func TestDecode_ExplicitZeroUnknown(t *testing.T) {
+	type config struct {
+		Metric *int `tf:"metric"`
+	}
+
+	decoded, err := decodeTFTagged[config](fakeConfigGetter{fakeGetter: fakeGetter{"metric": 0}, config: cty.ObjectVal(map[string]cty.Value{
+		"metric": cty.UnknownVal(cty.Number),
+	})})
+	require.NoError(t, err)
+	assert.Nil(t, decoded.Metric, "a value only known after apply isn't set yet")
+}
+
+func TestDecode_NestedExplicitZero(t *testing.T) {
+	type monitor struct {
+		Name       string `tf:"name"`
+		Enabled    bool   `tf:"enabled"`
+		Port       *int   `tf:"port"`
+		MaxLatency *int   `tf:"max_latency"`
+	}
+	type config struct {
+		Monitors []monitor `tf:"monitor"`
+	}
+
+	decoded, err := decodeTFTagged[config](fakeConfigGetter{
+		fakeGetter: fakeGetter{"monitor": []any{
+			map[string]any{"name": "a", "enabled": true, "port": 0, "max_latency": 0},
+			map[string]any{"name": "b", "enabled": false, "port": 443, "max_latency": 0},
+		}},
+		config: cty.ObjectVal(map[string]cty.Value{"monitor": cty.ListVal([]cty.Value{
+			// enabled comes from a default, so it's null in the config.
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("a"), "enabled": cty.NullVal(cty.Bool), "port": cty.Zero, "max_latency": cty.NullVal(cty.Number)}),
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("b"), "enabled": cty.False, "port": cty.NumberIntVal(443), "max_latency": cty.NullVal(cty.Number)}),
+		})}),
+	})
+	require.NoError(t, err)
+	require.Len(t, decoded.Monitors, 2)
+
+	assert.True(t, decoded.Monitors[0].Enabled, "defaults are kept")
+	require.NotNil(t, decoded.Monitors[0].Port)
+	assert.Equal(t, 0, *decoded.Monitors[0].Port)
+	assert.Nil(t, decoded.Monitors[0].MaxLatency)
+
+	require.NotNil(t, decoded.Monitors[1].Port)
+	assert.Equal(t, 443, *decoded.Monitors[1].Port)
+	assert.Nil(t, decoded.Monitors[1].MaxLatency)
+}
+
+// configuredData is resource data as it is during apply, with attrs planned from config.
+func configuredData(s map[string]*schema.Schema, attrs map[string]string, config map[string]cty.Value) *schema.ResourceData {
+	attrs["id"] = "res1"
+	config["id"] = cty.NullVal(cty.String)
+
+	res := schema.Resource{Schema: s}
+	return res.Data(&terraform.InstanceState{ID: "res1", Attributes: attrs, RawConfig: cty.ObjectVal(config)})
+}
+
+// payload is the JSON v marshals to.
+func payload(t *testing.T, v any) string {
+	t.Helper()
+	js, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(js)
+}
+
+func TestDecodeResourceData_ExplicitZero(t *testing.T) {
+	d := configuredData(map[string]*schema.Schema{
+		"dark_mode":  {Type: schema.TypeBool, Optional: true},
+		"forwarding": {Type: schema.TypeBool, Optional: true},
+	}, map[string]string{"dark_mode": "false", "forwarding": "false"}, map[string]cty.Value{
+		"dark_mode":  cty.False,
+		"forwarding": cty.NullVal(cty.Bool),
+	})
+
+	decoded, err := DecodeResourceData[NetworkConfig](d)
+	require.NoError(t, err)
+	require.NotNil(t, decoded.DarkMode)
+	assert.False(t, *decoded.DarkMode)
+	assert.Nil(t, decoded.Forwarding)
+
+	js := payload(t, decoded.ToTG())
+	assert.Contains(t, js, `"darkMode":false`)
+	assert.NotContains(t, js, `"forwarding"`)
+}
+
 func TestEncode_Simple(t *testing.T) {
 	var data struct {
 		Int    int     `tf:"int"`
@@ -407,3 +550,204 @@ func TestEncode_NestedStruct(t *testing.T) {
 		t.Errorf("error encoding data; client name should have been client1 but was %s", clients[0].(map[string]any)["name"])
 	}
 }
+
+type roundTripColor string
+
+type roundTripPort struct {
+	Number   int            `tf:"number" schema:"required"`
+	Protocol roundTripColor `tf:"protocol" schema:"optional"`
+	Tags     []string       `tf:"tags" schema:"optional"`
+}
+
+type roundTripLimits struct {
+	CPU    int     `tf:"cpu" schema:"optional"`
+	Memory float64 `tf:"memory" schema:"optional"`
+}
+
+type roundTripCommon struct {
+	Description string `tf:"description" schema:"optional"`
+	Enabled     bool   `tf:"enabled" schema:"optional"`
+}
+
+type roundTrip struct {
+	roundTripCommon
+
+	Name     string            `tf:"name" schema:"required"`
+	Color    roundTripColor    `tf:"color" schema:"optional"`
+	Count    uint16            `tf:"count" schema:"optional"`
+	Ratio    float64           `tf:"ratio" schema:"optional"`
+	MTU      int               `tf:"mtu,omitempty" schema:"optional"`
+	Weight   *int              `tf:"weight" schema:"optional"`
+	Aliases  []string          `tf:"aliases" schema:"optional,set"`
+	Matrix   [][]int           `tf:"matrix" schema:"optional"`
+	Labels   map[string]string `tf:"labels" schema:"optional"`
+	Quotas   map[string]int    `tf:"quotas" schema:"optional"`
+	Ports    []roundTripPort   `tf:"port" schema:"optional"`
+	Limits   roundTripLimits   `tf:"limits" schema:"optional"`
+	Override *roundTripLimits  `tf:"override" schema:"optional"`
+}
+
+func randomString(r *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, 1+r.IntN(8))
+	for i := range b {
+		b[i] = letters[r.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+// randomRoundTrip builds a random roundTrip. Top-level empty slices and maps are nil, nested ones
+// aren't empty, and pointers are nil or point at non-zero values, since the SDK can't tell those
+// apart without a config.
+func randomRoundTrip(r *rand.Rand) roundTrip {
+	var rt roundTrip
+	rt.Description = randomString(r)
+	rt.Enabled = r.IntN(2) == 0
+	rt.Name = randomString(r)
+	rt.Color = roundTripColor(randomString(r))
+	rt.Count = uint16(r.IntN(1000))
+	rt.Ratio = float64(r.IntN(1000)) / 8
+	if r.IntN(2) == 0 {
+		rt.MTU = 1 + r.IntN(9000)
+	}
+	if r.IntN(2) == 0 {
+		weight := 1 + r.IntN(100)
+		rt.Weight = &weight
+	}
+	for range r.IntN(4) {
+		rt.Aliases = append(rt.Aliases, randomString(r))
+	}
+	for range r.IntN(3) {
+		row := make([]int, 1+r.IntN(3))
+		for i := range row {
+			row[i] = r.IntN(100)
+		}
+		rt.Matrix = append(rt.Matrix, row)
+	}
+	for range r.IntN(3) {
+		if rt.Labels == nil {
+			rt.Labels, rt.Quotas = map[string]string{}, map[string]int{}
+		}
+		rt.Labels[randomString(r)] = randomString(r)
+		rt.Quotas[randomString(r)] = r.IntN(100)
+	}
+	for range r.IntN(3) {
+		port := roundTripPort{Number: r.IntN(65536), Protocol: roundTripColor(randomString(r))}
+		for range 1 + r.IntN(3) {
+			port.Tags = append(port.Tags, randomString(r))
+		}
+		rt.Ports = append(rt.Ports, port)
+	}
+	rt.Limits = roundTripLimits{CPU: r.IntN(100), Memory: float64(r.IntN(100)) / 4}
+	if r.IntN(2) == 0 {
+		rt.Override = &roundTripLimits{CPU: r.IntN(100)}
+	}
+	return rt
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	res := &schema.Resource{Schema: Schema[roundTrip](nil)}
+	require.NoError(t, res.InternalValidate(nil, true))
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 200 {
+		want := randomRoundTrip(r)
+
+		d := res.TestResourceData()
+		require.NoError(t, EncodeResourceData(want, d), "case %d", i)
+		got, err := DecodeResourceData[roundTrip](d)
+		require.NoError(t, err, "case %d", i)
+
+		// Sets come back in hash order.
+		slices.Sort(want.Aliases)
+		slices.Sort(got.Aliases)
+		require.Equal(t, want, got, "case %d", i)
+	}
+}
+
+func TestEncode_OmitEmptyClears(t *testing.T) {
+	res := &schema.Resource{Schema: Schema[roundTrip](nil)}
+	d := res.TestResourceData()
+
+	require.NoError(t, EncodeResourceData(roundTrip{Name: "a", MTU: 1500}, d))
+	assert.Equal(t, 1500, d.Get("mtu"))
+
+	require.NoError(t, EncodeResourceData(roundTrip{Name: "a"}, d))
+	_, ok := d.GetOk("mtu")
+	assert.False(t, ok)
+}
+
+func TestEncode_NilPointersLeftAlone(t *testing.T) {
+	res := &schema.Resource{Schema: Schema[roundTrip](nil)}
+	d := res.TestResourceData()
+
+	weight := 5
+	require.NoError(t, EncodeResourceData(roundTrip{Weight: &weight}, d))
+	require.NoError(t, EncodeResourceData(roundTrip{}, d))
+	assert.Equal(t, 5, d.Get("weight"))
+}
+
+func TestConvertToMap_Shapes(t *testing.T) {
+	type inner struct {
+		Name string `tf:"name"`
+	}
+	type outer struct {
+		Block   *inner           `tf:"block"`
+		Nil     *inner           `tf:"nil"`
+		ByName  map[string]inner `tf:"by_name"`
+		Ignored string           `tf:"-"`
+	}
+
+	out, err := convertToMap(outer{
+		Block:  &inner{Name: "a"},
+		ByName: map[string]inner{"b": {Name: "b"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"block":   []any{map[string]any{"name": "a"}},
+		"by_name": map[string]any{"b": map[string]any{"name": "b"}},
+	}, out)
+}
+
+func TestConvertToMap_Errors(t *testing.T) {
+	type badItem struct {
+		Ch chan int `tf:"ch"`
+	}
+	type badKeys struct {
+		Items []badItem    `tf:"item"`
+		Keys  map[int]bool `tf:"keys"`
+	}
+	type badBlock struct {
+		Block struct {
+			Fn func() `tf:"fn"`
+		} `tf:"block"`
+	}
+
+	_, err := convertToMap(badKeys{Items: []badItem{{}, {Ch: make(chan int)}}})
+	require.EqualError(t, err, "item.0.ch: unsupported type chan int")
+
+	_, err = convertToMap(badKeys{Keys: map[int]bool{1: true}})
+	require.EqualError(t, err, "keys: map keys must be strings, got int")
+
+	_, err = convertToMap(badBlock{})
+	require.EqualError(t, err, "block.0.fn: unsupported type func()")
+
+	_, err = convertToMap("nope")
+	require.Error(t, err)
+}
+
+func TestDecode_SingleBlockErrors(t *testing.T) {
+	type limits struct {
+		CPU int `tf:"cpu"`
+	}
+	type config struct {
+		Limits *limits `tf:"limits"`
+	}
+
+	_, err := decodeTFTagged[config](fakeGetter{"limits": []any{
+		map[string]any{"cpu": 1},
+		map[string]any{"cpu": 2},
+	}})
+	require.ErrorContains(t, err, "'limits'")
+	require.ErrorContains(t, err, "expected a single block, got 2")
+}
@@ -12,11 +12,11 @@ type GatewayConfig struct {
 
 	Enabled            bool   `tf:"enabled"`
 	Host               string `tf:"host"`
-	Port               int    `tf:"port"`
+	Port               *int   `tf:"port"`
 	MaxMBPS            int    `tf:"maxmbps"`
 	ConnectToPublic    bool   `tf:"connect_to_public"`
 	Type               string `tf:"type,omitempty"`
-	MonitorHops        bool   `tf:"monitor_hops"`
+	MonitorHops        *bool  `tf:"monitor_hops"`
 	MaxClientWriteMBPS int    `tf:"max_client_write_mbps"`
 
 	UDPEnabled bool `tf:"udp_enabled"`
@@ -0,0 +1,29 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayConfig_ExplicitZeroPayload(t *testing.T) {
+	d := configuredData(map[string]*schema.Schema{
+		"node_id":      {Type: schema.TypeString, Required: true},
+		"port":         {Type: schema.TypeInt, Optional: true, Computed: true},
+		"monitor_hops": {Type: schema.TypeBool, Optional: true},
+	}, map[string]string{"node_id": "node1", "port": "0", "monitor_hops": "false"}, map[string]cty.Value{
+		"node_id":      cty.StringVal("node1"),
+		"port":         cty.NullVal(cty.Number),
+		"monitor_hops": cty.False,
+	})
+
+	decoded, err := DecodeResourceData[GatewayConfig](d)
+	require.NoError(t, err)
+
+	js := payload(t, decoded.ToTG())
+	assert.Contains(t, js, `"monitorHops":false`)
+	assert.NotContains(t, js, `"port"`)
+}
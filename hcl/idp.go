@@ -98,7 +98,10 @@ func (idp *IDPOpenIDConfig) ToTG() tg.IDPOpenIDConfig {
 func (idp *IDPOpenIDConfig) UpdateFromTG(o tg.IDPOpenIDConfig) {
 	idp.Issuer = o.Issuer
 	idp.ClientID = o.ClientID
-	idp.Secret = o.Secret
+	// The API may not send the secret back; keep the one from state so it isn't planned as a change.
+	if o.Secret != "" {
+		idp.Secret = o.Secret
+	}
 	idp.AuthEndpoint = o.AuthEndpoint
 	idp.TokenEndpoint = o.TokenEndpoint
 	idp.UserInfoEndpoint = o.UserInfoEndpoint
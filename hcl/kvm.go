@@ -41,7 +41,7 @@ type KVMVolume struct {
 
 	Name          string `tf:"name"`
 	DeviceType    string `tf:"device_type"`
-	DeviseBus     string `tf:"device_bus"`
+	DeviceBus     string `tf:"device_bus"`
 	Size          int    `tf:"size"`
 	ProvisionType string `tf:"provision_type"`
 	Encrypted     bool   `tf:"encrypted"`
@@ -52,7 +52,7 @@ func (h *KVMVolume) ToTG() *tg.KVMVolume {
 	return &tg.KVMVolume{
 		Name:          h.Name,
 		DeviceType:    h.DeviceType,
-		DeviseBus:     h.DeviseBus,
+		DeviceBus:     h.DeviceBus,
 		Size:          h.Size,
 		ProvisionType: h.ProvisionType,
 		Path:          h.Path,
@@ -70,7 +70,7 @@ func (h *KVMVolume) ResourceURL() string {
 
 func (h *KVMVolume) UpdateFromTG(r tg.KVMVolume) {
 	h.DeviceType = r.DeviceType
-	h.DeviseBus = r.DeviseBus
+	h.DeviceBus = r.DeviceBus
 	h.Size = r.Size
 	h.ProvisionType = r.ProvisionType
 	h.Path = r.Path
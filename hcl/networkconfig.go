@@ -3,128 +3,129 @@ package hcl
 import "github.com/trustgrid/terraform-provider-tg/tg"
 
 type NetworkTunnel struct {
-	Enabled       bool   `tf:"enabled"`
-	Name          string `tf:"name"`
-	IKE           int    `tf:"ike,omitempty"`
-	IKECipher     string `tf:"ike_cipher,omitempty"`
-	IKEGroup      int    `tf:"ike_group,omitempty"`
-	RekeyInterval int    `tf:"rekey_interval,omitempty"`
-	IP            string `tf:"ip,omitempty"`
-	Destination   string `tf:"destination,omitempty"`
-	IPSecCipher   string `tf:"ipsec_cipher,omitempty"`
-	PSK           string `tf:"psk,omitempty"`
-	VRF           string `tf:"vrf,omitempty"`
-	Type          string `tf:"type"`
-	MTU           int    `tf:"mtu"`
-	NetworkID     int    `tf:"network_id"`
-	LocalID       string `tf:"local_id,omitempty"`
-	RemoteID      string `tf:"remote_id,omitempty"`
-	DPDRetries    int    `tf:"dpd_retries,omitempty"`
-	DPDInterval   int    `tf:"dpd_interval,omitempty"`
-	IFace         string `tf:"iface,omitempty"`
-	PFS           int    `tf:"pfs"`
-	ReplayWindow  int    `tf:"replay_window,omitempty"`
-	RemoteSubnet  string `tf:"remote_subnet,omitempty"`
-	LocalSubnet   string `tf:"local_subnet,omitempty"`
-	Description   string `tf:"description,omitempty"`
+	Enabled       bool   `tf:"enabled" schema:"required" description:"Enable the tunnel"`
+	Name          string `tf:"name" schema:"required" description:"Tunnel name"`
+	IKE           int    `tf:"ike,omitempty" schema:"optional" description:"IKE" validate:"in=1 2"`
+	IKECipher     string `tf:"ike_cipher,omitempty" schema:"optional" description:"IKE Cipher" validate:"oneof=aes128-sha1 aes128-sha256 aes256-sha1 aes256-sha256"`
+	IKEGroup      int    `tf:"ike_group,omitempty" schema:"optional" description:"IKE Group" validate:"in=2 5 14 15 16"`
+	RekeyInterval int    `tf:"rekey_interval,omitempty" schema:"optional" description:"Rekey Interval"`
+	IP            string `tf:"ip,omitempty" schema:"optional" description:"IP" validate:"cidr"`
+	Destination   string `tf:"destination,omitempty" schema:"optional" description:"Destination" validate:"ipv4"`
+	IPSecCipher   string `tf:"ipsec_cipher,omitempty" schema:"optional" description:"IPSec Cipher" validate:"oneof=aes128-sha1 aes128-sha256 aes256-sha1 aes256-sha256"`
+	PSK           string `tf:"psk,omitempty" schema:"optional,sensitive" description:"PSK"`
+	VRF           string `tf:"vrf,omitempty" schema:"optional" description:"VRF"`
+	Type          string `tf:"type" schema:"required" description:"Tunnel type" validate:"oneof=ipsec vnet"`
+	MTU           int    `tf:"mtu" schema:"required" description:"MTU"`
+	NetworkID     int    `tf:"network_id" schema:"optional" description:"Network ID"`
+	LocalID       string `tf:"local_id,omitempty" schema:"optional" description:"Local ID"`
+	RemoteID      string `tf:"remote_id,omitempty" schema:"optional" description:"Remote ID"`
+	DPDRetries    int    `tf:"dpd_retries,omitempty" schema:"optional" description:"DPD Retries"`
+	DPDInterval   int    `tf:"dpd_interval,omitempty" schema:"optional" description:"DPD Interval"`
+	IFace         string `tf:"iface,omitempty" schema:"optional" description:"Interface"`
+	PFS           int    `tf:"pfs" schema:"optional" description:"PFS" validate:"in=0 2 5 14 15 16"`
+	ReplayWindow  int    `tf:"replay_window,omitempty" schema:"optional" description:"Replay Window" validate:"in=32 64 128 256 512 1024 2048 4096 8192"`
+	RemoteSubnet  string `tf:"remote_subnet,omitempty" schema:"optional" description:"Interesting traffic remote subnet" validate:"cidr"`
+	LocalSubnet   string `tf:"local_subnet,omitempty" schema:"optional" description:"Interesting traffic local subnet" validate:"cidr"`
+	Description   string `tf:"description,omitempty" schema:"optional" description:"Description"`
 }
 
 type CloudRoute struct {
-	Route       string `tf:"route"`
-	Description string `tf:"description"`
+	Route       string `tf:"route" schema:"required" description:"Destination CIDR" validate:"cidr"`
+	Description string `tf:"description" schema:"optional" description:"Description"`
 }
 
 type NetworkRoute struct {
-	Route       string `tf:"route"`
-	Description string `tf:"description"`
-	NextHop     string `tf:"next_hop,omitempty"`
+	Route       string `tf:"route" schema:"required" description:"Destination CIDR" validate:"cidr"`
+	Description string `tf:"description" schema:"optional" description:"Description"`
+	NextHop     string `tf:"next_hop,omitempty" schema:"optional" description:"Next Hop" validate:"ipv4"`
 }
 
 type VLANRoute struct {
-	Route       string `tf:"route"`
-	Next        string `tf:"next,omitempty"`
-	Description string `tf:"description,omitempty"`
+	Route       string `tf:"route" schema:"required" description:"Destination CIDR" validate:"cidr"`
+	Next        string `tf:"next,omitempty" schema:"optional" description:"Next IP" validate:"ipv4"`
+	Description string `tf:"description,omitempty" schema:"optional" description:"Description"`
 }
 
 type SubInterface struct {
-	VLANID        int         `tf:"vlan_id"`
-	IP            string      `tf:"ip"`
-	VRF           string      `tf:"vrf,omitempty"`
-	AdditionalIPs []string    `tf:"additional_ips,omitempty"`
-	Routes        []VLANRoute `tf:"route,omitempty"`
-	Description   string      `tf:"description,omitempty"`
+	VLANID        int         `tf:"vlan_id" schema:"required" description:"VLAN ID" validate:"between=0 4095"`
+	IP            string      `tf:"ip" schema:"required" description:"IP CIDR" validate:"cidr"`
+	VRF           string      `tf:"vrf,omitempty" schema:"optional" description:"VRF"`
+	AdditionalIPs []string    `tf:"additional_ips,omitempty" schema:"optional" description:"Additional IP CIDRs" validate:"cidr"`
+	Routes        []VLANRoute `tf:"route,omitempty" schema:"optional" description:"VLAN routes"`
+	Description   string      `tf:"description,omitempty" schema:"optional" description:"Description"`
 }
 
 type NetworkInterface struct {
-	NIC                string         `tf:"nic"`
-	VRF                string         `tf:"vrf,omitempty"`
-	Routes             []NetworkRoute `tf:"route,omitempty"`
-	SubInterfaces      []SubInterface `tf:"subinterface,omitempty"`
-	CloudRoutes        []CloudRoute   `tf:"cloud_route,omitempty"`
-	ClusterRouteTables []string       `tf:"cluster_route_tables,omitempty"`
-	ClusterIP          string         `tf:"cluster_ip,omitempty"`
-	DHCP               bool           `tf:"dhcp"`
-	Gateway            string         `tf:"gateway"`
-	IP                 string         `tf:"ip"`
-	Mode               string         `tf:"mode,omitempty"`
-	DNS                []string       `tf:"dns,omitempty"`
-	Duplex             string         `tf:"duplex,omitempty"`
-	Speed              int            `tf:"speed,omitempty"`
-	MTU                int            `tf:"mtu,omitempty"`
+	NIC                string         `tf:"nic" schema:"required" description:"NIC name"`
+	VRF                string         `tf:"vrf,omitempty" schema:"optional" description:"VRF"`
+	Routes             []NetworkRoute `tf:"route,omitempty" schema:"optional" description:"Interface routes"`
+	SubInterfaces      []SubInterface `tf:"subinterface,omitempty" schema:"optional" description:"VLAN interfaces"`
+	CloudRoutes        []CloudRoute   `tf:"cloud_route,omitempty" schema:"optional" description:"Cluster interface routes - these will update AWS/Azure VPC route tables"`
+	ClusterRouteTables []string       `tf:"cluster_route_tables,omitempty" schema:"optional" description:"Cluster route tables - should be a list of either AWS or Azure route table IDs"`
+	ClusterIP          string         `tf:"cluster_ip,omitempty" schema:"optional,conflicts=node_id" description:"Cluster IP" validate:"ipv4"`
+	DHCP               *bool          `tf:"dhcp" schema:"optional" description:"Enable DHCP. Only applicable to WAN interfaces."`
+	Gateway            string         `tf:"gateway" schema:"optional" description:"Gateway IP address" validate:"ipv4"`
+	IP                 string         `tf:"ip" schema:"optional,conflicts=cluster_fqdn" description:"IP address" validate:"cidr"`
+	Mode               string         `tf:"mode,omitempty" schema:"optional" description:"Auto Negotiation mode. Valid values are \"auto\" and \"manual\". When set to \"manual\", speed and duplex must also be provided." validate:"oneof=auto manual"`
+	DNS                []string       `tf:"dns,omitempty" schema:"optional" description:"DNS servers" validate:"ipv4"`
+	Duplex             string         `tf:"duplex,omitempty" schema:"optional" description:"Interface duplex (full or half). Must be provided alongside mode." validate:"oneof=full half"`
+	Speed              int            `tf:"speed,omitempty" schema:"optional" description:"Interface speed in Mbps. Valid values are 10, 100, 1000, 2500, 5000, 10000. Must be provided alongside mode." validate:"in=10 100 1000 2500 5000 10000"`
+	MTU                *int           `tf:"mtu,omitempty" schema:"optional" description:"Interface MTU"`
 }
 
 type VRFACL struct {
-	Action      string `tf:"action"`
-	Description string `tf:"description"`
-	Protocol    string `tf:"protocol"`
-	Source      string `tf:"source"`
-	Dest        string `tf:"dest"`
-	Line        int    `tf:"line"`
+	Action      string `tf:"action" schema:"required" description:"Action" validate:"oneof=allow drop reject"`
+	Description string `tf:"description" schema:"optional" description:"Description"`
+	Protocol    string `tf:"protocol" schema:"required" description:"Protocol" validate:"oneof=any icmp tcp udp"`
+	Source      string `tf:"source" schema:"required" description:"Source" validate:"cidr"`
+	Dest        string `tf:"dest" schema:"required" description:"Destination" validate:"cidr"`
+	Line        int    `tf:"line" schema:"optional" description:"Line" validate:"between=1 32768"`
 }
 
 type VRFRoute struct {
-	Dest        string `tf:"dest"`
-	Dev         string `tf:"dev"`
-	Description string `tf:"description"`
-	Metric      int    `tf:"metric"`
+	Dest        string `tf:"dest" schema:"required" description:"Destination" validate:"cidr"`
+	Dev         string `tf:"dev" schema:"required" description:"Dev"`
+	Description string `tf:"description" schema:"optional" description:"Description"`
+	Metric      int    `tf:"metric" schema:"optional" description:"Metric" validate:"between=1 200"`
 }
 
 type VRFNAT struct {
-	Source     string `tf:"source,omitempty"`
-	Dest       string `tf:"dest,omitempty"`
-	Masquerade bool   `tf:"masquerade"`
-	ToSource   string `tf:"to_source,omitempty"`
-	ToDest     string `tf:"to_dest,omitempty"`
+	Description string `tf:"description,omitempty" schema:"optional" description:"Description"`
+	Source      string `tf:"source,omitempty" schema:"optional" description:"Source" validate:"cidr"`
+	Dest        string `tf:"dest,omitempty" schema:"optional" description:"Destination" validate:"cidr"`
+	Masquerade  bool   `tf:"masquerade" schema:"optional" description:"Masquerade"`
+	ToSource    string `tf:"to_source,omitempty" schema:"optional" description:"To Source" validate:"cidr"`
+	ToDest      string `tf:"to_dest,omitempty" schema:"optional" description:"To Dest" validate:"cidr"`
 }
 
 type VRFRule struct {
-	Protocol    string `tf:"protocol"`
-	Line        int    `tf:"line"`
-	Action      string `tf:"action"`
-	Description string `tf:"description,omitempty"`
-	Source      string `tf:"source,omitempty"`
-	VRF         string `tf:"vrf,omitempty"`
-	Dest        string `tf:"dest,omitempty"`
+	Protocol    string `tf:"protocol" schema:"required" description:"Protocol" validate:"oneof=any icmp tcp udp"`
+	Line        int    `tf:"line" schema:"optional" description:"Line" validate:"between=1 32768"`
+	Action      string `tf:"action" schema:"optional" description:"To Dest" validate:"oneof=accept drop reject forward dnat"`
+	Description string `tf:"description,omitempty" schema:"optional" description:"Description"`
+	Source      string `tf:"source,omitempty" schema:"required" description:"Source" validate:"cidr"`
+	VRF         string `tf:"vrf,omitempty" schema:"optional" description:"VRF"`
+	Dest        string `tf:"dest,omitempty" schema:"required" description:"Destination" validate:"cidr"`
 }
 
 type VRF struct {
-	Name       string     `tf:"name"`
-	Forwarding bool       `tf:"forwarding"`
-	ACLs       []VRFACL   `tf:"acl,omitempty"`
-	Routes     []VRFRoute `tf:"route,omitempty"`
-	NATs       []VRFNAT   `tf:"nat,omitempty"`
-	Rules      []VRFRule  `tf:"rule,omitempty"`
+	Name       string     `tf:"name" schema:"required" description:"VRF name"`
+	Forwarding bool       `tf:"forwarding" schema:"optional" description:"Enable forwarding"`
+	ACLs       []VRFACL   `tf:"acl,omitempty" schema:"optional" description:"ACLs"`
+	Routes     []VRFRoute `tf:"route,omitempty" schema:"optional" description:"Routes"`
+	NATs       []VRFNAT   `tf:"nat,omitempty" schema:"optional" description:"NATs"`
+	Rules      []VRFRule  `tf:"rule,omitempty" schema:"optional" description:"Rules"`
 }
 
 type NetworkConfig struct {
-	DarkMode   *bool `tf:"dark_mode"`
-	Forwarding *bool `tf:"forwarding"`
+	DarkMode   *bool `tf:"dark_mode" schema:"optional" description:"Dark mode"`
+	Forwarding *bool `tf:"forwarding" schema:"optional" description:"Forwarding"`
 
-	Tunnels []NetworkTunnel `tf:"tunnel"`
+	Tunnels []NetworkTunnel `tf:"tunnel" schema:"optional" description:"Network tunnels"`
 
-	Interfaces []NetworkInterface `tf:"interface"`
+	Interfaces []NetworkInterface `tf:"interface" schema:"optional" description:"Network interfaces"`
 
-	VRFs []VRF `tf:"vrf"`
+	VRFs []VRF `tf:"vrf" schema:"optional" description:"VRFs"`
 }
 
 func (v VLANRoute) ToTG() tg.VLANRoute {
@@ -176,11 +177,12 @@ func (r VRFRule) ToTG() tg.VRFRule {
 
 func (n VRFNAT) ToTG() tg.VRFNAT {
 	return tg.VRFNAT{
-		Source:     n.Source,
-		Dest:       n.Dest,
-		Masquerade: n.Masquerade,
-		ToSource:   n.ToSource,
-		ToDest:     n.ToDest,
+		Description: n.Description,
+		Source:      n.Source,
+		Dest:        n.Dest,
+		Masquerade:  n.Masquerade,
+		ToSource:    n.ToSource,
+		ToDest:      n.ToDest,
 	}
 }
 
@@ -380,11 +382,12 @@ func (h *NetworkConfig) UpdateFromTG(c tg.NetworkConfig) {
 
 		for _, n := range v.NATs {
 			vrf.NATs = append(vrf.NATs, VRFNAT{
-				Source:     n.Source,
-				Dest:       n.Dest,
-				Masquerade: n.Masquerade,
-				ToSource:   n.ToSource,
-				ToDest:     n.ToDest,
+				Description: n.Description,
+				Source:      n.Source,
+				Dest:        n.Dest,
+				Masquerade:  n.Masquerade,
+				ToSource:    n.ToSource,
+				ToDest:      n.ToDest,
 			})
 		}
 
@@ -0,0 +1,32 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkInterface_ExplicitZeroPayload(t *testing.T) {
+	d := configuredData(Schema[NetworkConfig](nil), map[string]string{
+		"interface.#":      "1",
+		"interface.0.nic":  "ens192",
+		"interface.0.dhcp": "false",
+		"interface.0.mtu":  "0",
+	}, map[string]cty.Value{
+		"interface": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"nic":  cty.StringVal("ens192"),
+			"dhcp": cty.False,
+			"mtu":  cty.NullVal(cty.Number),
+		})}),
+	})
+
+	decoded, err := DecodeResourceData[NetworkConfig](d)
+	require.NoError(t, err)
+	require.Len(t, decoded.Interfaces, 1)
+
+	js := payload(t, decoded.Interfaces[0].ToTG())
+	assert.Contains(t, js, `"dhcp":false`)
+	assert.NotContains(t, js, `"mtu"`)
+}
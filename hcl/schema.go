@@ -0,0 +1,339 @@
+package hcl
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/trustgrid/terraform-provider-tg/validators"
+)
+
+// validatorTags are the validators a `validate` tag can name. Those taking arguments read them
+// from after the `=`, space separated, like `validate:"oneof=tcp udp"`.
+var validatorTags = map[string]func(args []string) (schema.SchemaValidateFunc, error){
+	"cidr":     noArgs(validation.IsCIDR),
+	"ipv4":     noArgs(validation.IsIPv4Address),
+	"uuid":     noArgs(validation.IsUUID),
+	"port":     noArgs(validation.IsPortNumber),
+	"hostname": noArgs(validators.IsHostname),
+	"oneof": func(args []string) (schema.SchemaValidateFunc, error) {
+		return validation.StringInSlice(args, false), nil
+	},
+	"in": func(args []string) (schema.SchemaValidateFunc, error) {
+		ints, err := atois(args)
+		if err != nil {
+			return nil, err
+		}
+		return validation.IntInSlice(ints), nil
+	},
+	"between": func(args []string) (schema.SchemaValidateFunc, error) {
+		ints, err := atois(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(ints) != 2 {
+			return nil, fmt.Errorf("expected a minimum and maximum, got %v", args)
+		}
+		return validation.IntBetween(ints[0], ints[1]), nil
+	},
+}
+
+func noArgs(f schema.SchemaValidateFunc) func([]string) (schema.SchemaValidateFunc, error) {
+	return func(args []string) (schema.SchemaValidateFunc, error) {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("unexpected arguments %v", args)
+		}
+		return f, nil
+	}
+}
+
+func atois(args []string) ([]int, error) {
+	ints := make([]int, 0, len(args))
+	for _, a := range args {
+		i, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+// Schema builds a resource schema from the tags on T's fields:
+//
+//   - `tf` names the attribute, as for `DecodeResourceData`. Fields without one are left out.
+//   - `description` is the attribute description.
+//   - `schema` lists `required`, `optional`, `computed`, `forcenew` and `sensitive`, plus `set` to
+//     make a slice a set rather than a list, `min=N` and `max=N` for the number of items,
+//     `default=V`, and `conflicts=` and `exactlyoneof=` with space separated attribute names.
+//   - `validate` names one of the validators in `validatorTags`. On a slice of primitives it
+//     validates each item.
+//
+// Slices of structs become nested blocks, structs a block of at most one item, and
+// `map[string]string` a map. Pointers are treated as what they point at, and the fields of
+// embedded structs without a `tf` tag as the parent's own.
+//
+// overrides replace or add attributes the tags can't describe. Nested attributes are named by
+// their path, like `interface.cluster_ip`, and a nil override removes the attribute.
+//
+// Schema panics if the tags are invalid, since that's a bug in the provider itself.
+func Schema[T any](overrides map[string]*schema.Schema) map[string]*schema.Schema {
+	sm, err := structSchema(reflect.TypeFor[T]())
+	if err != nil {
+		panic(fmt.Sprintf("building schema for %s: %s", reflect.TypeFor[T](), err))
+	}
+
+	for path, s := range overrides {
+		if err := override(sm, strings.Split(path, "."), s); err != nil {
+			panic(fmt.Sprintf("overriding %s in schema for %s: %s", path, reflect.TypeFor[T](), err))
+		}
+	}
+
+	return sm
+}
+
+func structSchema(t reflect.Type) (map[string]*schema.Schema, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %s", t)
+	}
+
+	sm := make(map[string]*schema.Schema)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if embedded(field) {
+			promoted, err := structSchema(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			maps.Copy(sm, promoted)
+			continue
+		}
+
+		name, _ := tfName(field)
+		if name == "" || name == "-" {
+			continue
+		}
+
+		s, err := fieldSchema(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		sm[name] = s
+	}
+	return sm, nil
+}
+
+func fieldSchema(field reflect.StructField) (*schema.Schema, error) {
+	s := &schema.Schema{Description: field.Tag.Get("description")}
+
+	var validate schema.SchemaValidateFunc
+	if tag := field.Tag.Get("validate"); tag != "" {
+		name, args, _ := strings.Cut(tag, "=")
+		build, ok := validatorTags[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown validator %q", name)
+		}
+		var err error
+		if validate, err = build(strings.Fields(args)); err != nil {
+			return nil, fmt.Errorf("validator %s: %w", name, err)
+		}
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	//exhaustive:ignore
+	switch t.Kind() {
+	case reflect.Struct:
+		if validate != nil {
+			return nil, fmt.Errorf("validators aren't supported on blocks")
+		}
+		nested, err := structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		s.Type = schema.TypeList
+		s.MaxItems = 1
+		s.Elem = &schema.Resource{Schema: nested}
+	case reflect.Slice:
+		s.Type = schema.TypeList
+		elem := t.Elem()
+		if elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct {
+			if validate != nil {
+				return nil, fmt.Errorf("validators aren't supported on blocks")
+			}
+			nested, err := structSchema(elem)
+			if err != nil {
+				return nil, err
+			}
+			s.Elem = &schema.Resource{Schema: nested}
+			break
+		}
+		item, err := itemSchema(elem, validate)
+		if err != nil {
+			return nil, err
+		}
+		s.Elem = item
+	case reflect.Map:
+		item, err := itemSchema(t, validate)
+		if err != nil {
+			return nil, err
+		}
+		*s = schema.Schema{Type: item.Type, Description: s.Description, Elem: item.Elem}
+	default:
+		vt, err := primitiveType(t)
+		if err != nil {
+			return nil, err
+		}
+		s.Type = vt
+		s.ValidateFunc = validate
+	}
+
+	if err := applyOptions(s, field.Tag.Get("schema")); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// itemSchema is the schema of the items of a list or map of t, which is a primitive or a list or
+// map of them. validate applies to the innermost primitives.
+func itemSchema(t reflect.Type, validate schema.SchemaValidateFunc) (*schema.Schema, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	//exhaustive:ignore
+	switch t.Kind() {
+	case reflect.Slice:
+		elem, err := itemSchema(t.Elem(), validate)
+		if err != nil {
+			return nil, err
+		}
+		return &schema.Schema{Type: schema.TypeList, Elem: elem}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys must be strings, got %s", t.Key())
+		}
+		elem, err := itemSchema(t.Elem(), validate)
+		if err != nil {
+			return nil, err
+		}
+		if elem.Type == schema.TypeList || elem.Type == schema.TypeMap {
+			return nil, fmt.Errorf("map values must be primitives, got %s", t.Elem())
+		}
+		return &schema.Schema{Type: schema.TypeMap, Elem: elem}, nil
+	}
+
+	vt, err := primitiveType(t)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.Schema{Type: vt, ValidateFunc: validate}, nil
+}
+
+func primitiveType(t reflect.Type) (schema.ValueType, error) {
+	//exhaustive:ignore
+	switch t.Kind() {
+	case reflect.String:
+		return schema.TypeString, nil
+	case reflect.Bool:
+		return schema.TypeBool, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema.TypeInt, nil
+	case reflect.Float32, reflect.Float64:
+		return schema.TypeFloat, nil
+	}
+	return schema.TypeInvalid, fmt.Errorf("unsupported type %s", t)
+}
+
+// applyOptions sets the options in a `schema` tag on s, whose type is already known.
+func applyOptions(s *schema.Schema, tag string) error {
+	if tag == "" {
+		return nil
+	}
+
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		var err error
+		switch key {
+		case "required":
+			s.Required = true
+		case "optional":
+			s.Optional = true
+		case "computed":
+			s.Computed = true
+		case "forcenew":
+			s.ForceNew = true
+		case "sensitive":
+			s.Sensitive = true
+		case "set":
+			if s.Type != schema.TypeList {
+				return fmt.Errorf("set is only supported on slices")
+			}
+			s.Type = schema.TypeSet
+		case "min":
+			s.MinItems, err = strconv.Atoi(value)
+		case "max":
+			s.MaxItems, err = strconv.Atoi(value)
+		case "default":
+			s.Default, err = parseDefault(s.Type, value)
+		case "conflicts":
+			s.ConflictsWith = strings.Fields(value)
+		case "exactlyoneof":
+			s.ExactlyOneOf = strings.Fields(value)
+		default:
+			return fmt.Errorf("unknown schema option %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("schema option %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func parseDefault(t schema.ValueType, value string) (any, error) {
+	//exhaustive:ignore
+	switch t {
+	case schema.TypeString:
+		return value, nil
+	case schema.TypeBool:
+		return strconv.ParseBool(value)
+	case schema.TypeInt:
+		return strconv.Atoi(value)
+	case schema.TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	}
+	return nil, fmt.Errorf("defaults aren't supported on %s", t)
+}
+
+// override replaces the attribute at path in sm with s, or removes it if s is nil.
+func override(sm map[string]*schema.Schema, path []string, s *schema.Schema) error {
+	if len(path) == 1 {
+		if s == nil {
+			delete(sm, path[0])
+		} else {
+			sm[path[0]] = s
+		}
+		return nil
+	}
+
+	parent, ok := sm[path[0]]
+	if !ok {
+		return fmt.Errorf("no attribute %s", path[0])
+	}
+	block, ok := parent.Elem.(*schema.Resource)
+	if !ok {
+		return fmt.Errorf("%s isn't a block", path[0])
+	}
+	return override(block.Schema, path[1:], s)
+}
@@ -0,0 +1,105 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	type port struct {
+		Number   int    `tf:"number" schema:"required" validate:"port"`
+		Protocol string `tf:"protocol" schema:"optional,default=tcp" validate:"oneof=tcp udp"`
+	}
+	type thing struct {
+		Name    string            `tf:"name" schema:"required,forcenew" description:"Name"`
+		Secret  string            `tf:"secret" schema:"optional,sensitive"`
+		Weight  *int              `tf:"weight" schema:"optional,default=10" validate:"between=1 100"`
+		CIDRs   []string          `tf:"cidrs" schema:"optional,set" validate:"cidr"`
+		Tags    map[string]string `tf:"tags" schema:"optional"`
+		Ports   []port            `tf:"port" schema:"optional,max=2"`
+		Ignored string
+	}
+
+	sm := Schema[thing](nil)
+	require.NoError(t, schema.InternalMap(sm).InternalValidate(nil))
+	assert.Len(t, sm, 6)
+
+	assert.Equal(t, &schema.Schema{Type: schema.TypeString, Required: true, ForceNew: true, Description: "Name"}, sm["name"])
+	assert.True(t, sm["secret"].Sensitive)
+
+	assert.Equal(t, schema.TypeInt, sm["weight"].Type)
+	assert.Equal(t, 10, sm["weight"].Default)
+	_, errs := sm["weight"].ValidateFunc(101, "weight")
+	assert.NotEmpty(t, errs)
+
+	assert.Equal(t, schema.TypeSet, sm["cidrs"].Type)
+	_, errs = sm["cidrs"].Elem.(*schema.Schema).ValidateFunc("nope", "cidrs")
+	assert.NotEmpty(t, errs)
+
+	assert.Equal(t, schema.TypeMap, sm["tags"].Type)
+	assert.Equal(t, schema.TypeString, sm["tags"].Elem.(*schema.Schema).Type)
+
+	assert.Equal(t, 2, sm["port"].MaxItems)
+	ports := sm["port"].Elem.(*schema.Resource).Schema
+	assert.Equal(t, "tcp", ports["protocol"].Default)
+	_, errs = ports["protocol"].ValidateFunc("icmp", "protocol")
+	assert.NotEmpty(t, errs)
+}
+
+func TestSchema_Overrides(t *testing.T) {
+	type port struct {
+		Number int `tf:"number" schema:"required"`
+		Name   int `tf:"name" schema:"optional"`
+	}
+	type thing struct {
+		Name  string `tf:"name" schema:"required"`
+		Extra string `tf:"extra" schema:"optional"`
+		Ports []port `tf:"port" schema:"optional"`
+	}
+
+	sm := Schema[thing](map[string]*schema.Schema{
+		"name":        {Type: schema.TypeString, Optional: true, Computed: true},
+		"extra":       nil,
+		"port.number": {Type: schema.TypeInt, Optional: true},
+		"added":       {Type: schema.TypeBool, Computed: true},
+	})
+
+	assert.True(t, sm["name"].Computed)
+	assert.NotContains(t, sm, "extra")
+	assert.Contains(t, sm, "added")
+	ports := sm["port"].Elem.(*schema.Resource).Schema
+	assert.True(t, ports["number"].Optional)
+	assert.Contains(t, ports, "name")
+}
+
+func TestSchema_Invalid(t *testing.T) {
+	type unknownOption struct {
+		Name string `tf:"name" schema:"requried"`
+	}
+	type unknownValidator struct {
+		Name string `tf:"name" validate:"email"`
+	}
+	type badDefault struct {
+		Count int `tf:"count" schema:"default=many"`
+	}
+	type setOnString struct {
+		Name string `tf:"name" schema:"set"`
+	}
+	type unsupported struct {
+		Ch chan int `tf:"ch"`
+	}
+	type valid struct {
+		Name string `tf:"name" schema:"required"`
+	}
+
+	assert.PanicsWithValue(t, `building schema for hcl.unknownOption: name: unknown schema option "requried"`, func() { Schema[unknownOption](nil) })
+	assert.Panics(t, func() { Schema[unknownValidator](nil) })
+	assert.Panics(t, func() { Schema[badDefault](nil) })
+	assert.Panics(t, func() { Schema[setOnString](nil) })
+	assert.Panics(t, func() { Schema[unsupported](nil) })
+	assert.Panics(t, func() { Schema[valid](map[string]*schema.Schema{"missing.name": {Type: schema.TypeString}}) })
+	assert.Panics(t, func() { Schema[valid](map[string]*schema.Schema{"name.nested": {Type: schema.TypeString}}) })
+}
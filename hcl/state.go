@@ -0,0 +1,61 @@
+package hcl
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// rawState is resource state as Terraform stores it, like state upgraders get it.
+type rawState map[string]any
+
+func (s rawState) GetOk(key string) (any, bool) {
+	v, ok := s[key]
+	return v, ok && v != nil
+}
+
+// DecodeState decodes raw resource state, as handed to a state upgrader, into the given struct
+// using the `tf` tag, like `DecodeResourceData`.
+func DecodeState[T any](state map[string]any) (T, error) {
+	return decodeTFTagged[T](rawState(state))
+}
+
+// EncodeState encodes the given struct as raw resource state using the `tf` tag, like
+// `EncodeResourceData`.
+func EncodeState(in any) (map[string]any, error) {
+	v := reflect.ValueOf(in)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %T", in)
+	}
+	return encodeStruct("", v)
+}
+
+// UpgradeState decodes state written with an older schema into Old, upgrades it and encodes the
+// result. Attributes Old has no field for, like `id` or `timeouts`, are kept as they are, and
+// those it has are replaced by New's.
+func UpgradeState[Old any, New any](state map[string]any, upgrade func(Old) (New, error)) (map[string]any, error) {
+	old, err := DecodeState[Old](state)
+	if err != nil {
+		return nil, fmt.Errorf("decoding state: %w", err)
+	}
+
+	upgraded, err := upgrade(old)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := EncodeState(upgraded)
+	if err != nil {
+		return nil, fmt.Errorf("encoding state: %w", err)
+	}
+
+	out := maps.Clone(state)
+	for _, name := range tfNames(reflect.TypeFor[Old]()) {
+		delete(out, name)
+	}
+	maps.Copy(out, encoded)
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package hcl
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stateTarget struct {
+	Host string `tf:"host"`
+	Port int    `tf:"port"`
+}
+
+type stateV0 struct {
+	Name    string `tf:"name"`
+	Target  string `tf:"target"`
+	Retries int    `tf:"retries"`
+}
+
+type stateV1 struct {
+	Name    string       `tf:"name"`
+	Target  *stateTarget `tf:"target"`
+	Retries int          `tf:"retries"`
+	Labels  []string     `tf:"labels,omitempty"`
+}
+
+// state unmarshals JSON the way Terraform hands state to upgraders, numbers and all.
+func state(t *testing.T, raw string) map[string]any {
+	t.Helper()
+	var s map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &s))
+	return s
+}
+
+func TestDecodeState(t *testing.T) {
+	v1, err := DecodeState[stateV1](state(t, `{"name":"web","target":[{"host":"10.0.0.1","port":8080}],"retries":3,"labels":null}`))
+	require.NoError(t, err)
+	assert.Equal(t, stateV1{Name: "web", Target: &stateTarget{Host: "10.0.0.1", Port: 8080}, Retries: 3}, v1)
+
+	_, err = DecodeState[stateV0](state(t, `{"retries":"lots"}`))
+	assert.Error(t, err)
+}
+
+func TestEncodeState(t *testing.T) {
+	encoded, err := EncodeState(&stateV1{Name: "web", Target: &stateTarget{Host: "10.0.0.1", Port: 8080}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":    "web",
+		"target":  []any{map[string]any{"host": "10.0.0.1", "port": 8080}},
+		"retries": 0,
+		"labels":  nil,
+	}, encoded)
+
+	_, err = EncodeState("web")
+	assert.EqualError(t, err, "expected a struct, got string")
+}
+
+func TestUpgradeState(t *testing.T) {
+	old := state(t, `{"id":"p1","name":"web","target":"10.0.0.1:8080","retries":3,"timeouts":null}`)
+
+	upgraded, err := UpgradeState(old, func(v0 stateV0) (stateV1, error) {
+		host, port, _ := strings.Cut(v0.Target, ":")
+		target := &stateTarget{Host: host}
+		if err := json.Unmarshal([]byte(port), &target.Port); err != nil {
+			return stateV1{}, err
+		}
+		return stateV1{Name: v0.Name, Target: target, Retries: v0.Retries}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id":       "p1",
+		"timeouts": nil,
+		"name":     "web",
+		"target":   []any{map[string]any{"host": "10.0.0.1", "port": 8080}},
+		"retries":  3,
+		"labels":   nil,
+	}, upgraded)
+	assert.Equal(t, "10.0.0.1:8080", old["target"], "the old state is left alone")
+}
+
+func TestUpgradeState_DropsRenamedAttributes(t *testing.T) {
+	type renamed struct {
+		Title string `tf:"title"`
+	}
+
+	upgraded, err := UpgradeState(state(t, `{"id":"p1","name":"web"}`), func(v0 struct {
+		Name string `tf:"name"`
+	}) (renamed, error) {
+		return renamed{Title: v0.Name}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": "p1", "title": "web"}, upgraded)
+}
+
+func TestUpgradeState_Errors(t *testing.T) {
+	_, err := UpgradeState(state(t, `{"retries":"lots"}`), func(v0 stateV0) (stateV1, error) {
+		return stateV1{}, nil
+	})
+	assert.ErrorContains(t, err, "decoding state: ")
+
+	boom := errors.New("boom")
+	_, err = UpgradeState(state(t, `{}`), func(v0 stateV0) (stateV1, error) {
+		return stateV1{}, boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	_, err = UpgradeState(state(t, `{}`), func(v0 stateV0) (struct {
+		Ch chan int `tf:"ch"`
+	}, error) {
+		return struct {
+			Ch chan int `tf:"ch"`
+		}{Ch: make(chan int)}, nil
+	})
+	assert.EqualError(t, err, "encoding state: ch: unsupported type chan int")
+}
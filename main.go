@@ -1,7 +1,13 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/trustgrid/terraform-provider-tg/export"
 	"github.com/trustgrid/terraform-provider-tg/provider"
 )
 
@@ -25,7 +31,19 @@ var (
 )
 
 func main() {
-	opts := &plugin.ServeOpts{ProviderFunc: provider.New(version)}
+	// `terraform-provider-tg export --out dir` writes an existing org out as Terraform config.
+	if len(os.Args) > 1 && (os.Args[1] == "export" || os.Args[1] == "generate") {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := export.Run(ctx, provider.New(version)(), os.Args[2:], os.Stderr)
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts := &plugin.ServeOpts{GRPCProviderFunc: provider.Server(version)}
 
 	plugin.Serve(opts)
 }
@@ -0,0 +1,181 @@
+package majordomo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// placeholder matches the `{attribute}` parts of an import ID format. A placeholder can carry its
+// own pattern, as in `{line_number:[0-9]+}`; the pattern must not contain capture groups.
+var placeholder = regexp.MustCompile(`\{([a-z0-9_]+)(?::([^{}]+))?\}`)
+
+// importFormat is a compiled import ID format such as `cluster:{cluster_fqdn}/{uid}`.
+type importFormat struct {
+	attrs []string
+	re    *regexp.Regexp
+}
+
+// compileImportFormat turns a format into a regexp. Placeholders without a pattern stop at the
+// next `/` or `:`, except a trailing one, which takes the rest of the ID.
+func compileImportFormat(format string) importFormat {
+	var f importFormat
+	var b strings.Builder
+	b.WriteString("^")
+
+	last := 0
+	matches := placeholder.FindAllStringSubmatchIndex(format, -1)
+	for i, m := range matches {
+		b.WriteString(regexp.QuoteMeta(format[last:m[0]]))
+		switch {
+		case m[4] >= 0:
+			b.WriteString("(" + format[m[4]:m[5]] + ")")
+		case i == len(matches)-1 && m[1] == len(format):
+			b.WriteString("(.+)")
+		default:
+			b.WriteString("([^/:]+)")
+		}
+		f.attrs = append(f.attrs, format[m[2]:m[3]])
+		last = m[1]
+	}
+	b.WriteString(regexp.QuoteMeta(format[last:]))
+	b.WriteString("$")
+
+	f.re = regexp.MustCompile(b.String())
+	return f
+}
+
+// describeImportFormats renders formats the way users should type them, e.g. `cluster:<cluster_fqdn>/<uid>`.
+func describeImportFormats(formats []string) string {
+	described := make([]string, 0, len(formats))
+	for _, format := range formats {
+		described = append(described, "`"+placeholder.ReplaceAllString(format, "<$1>")+"`")
+	}
+	return strings.Join(described, " or ")
+}
+
+// ParseImportID matches id against each format in turn and returns the attribute values from
+// the first one that fits.
+func ParseImportID(id string, formats ...string) (map[string]string, error) {
+	for _, format := range formats {
+		f := compileImportFormat(format)
+		m := f.re.FindStringSubmatch(id)
+		if m == nil {
+			continue
+		}
+		attrs := make(map[string]string, len(f.attrs))
+		for i, attr := range f.attrs {
+			attrs[attr] = m[i+1]
+		}
+		return attrs, nil
+	}
+	return nil, fmt.Errorf("unexpected import ID %q, expected %s", id, describeImportFormats(formats))
+}
+
+// setImportAttributes writes parsed import attributes into the state, converting them to
+// numbers for integer attributes.
+func setImportAttributes(d *schema.ResourceData, attrs map[string]string) error {
+	for attr, value := range attrs {
+		if err := d.Set(attr, value); err != nil {
+			n, convErr := strconv.Atoi(value)
+			if convErr != nil {
+				return fmt.Errorf("%s: %w", attr, err)
+			}
+			if err := d.Set(attr, n); err != nil {
+				return fmt.Errorf("%s: %w", attr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Importer returns an importer for IDs in one of the given formats. It seeds the state with the
+// attributes named in the format, sets the resource ID with `id` (keeping the import ID if nil),
+// then calls `read` so a missing object fails the import instead of importing an empty resource.
+func Importer(read schema.ReadContextFunc, id func(*schema.ResourceData) (string, error), formats ...string) *schema.ResourceImporter {
+	if id == nil {
+		return LookupImporter(read, nil, formats...)
+	}
+	return LookupImporter(read, func(_ context.Context, d *schema.ResourceData, _ any) error {
+		stateID, err := id(d)
+		if err != nil {
+			return err
+		}
+		d.SetId(stateID)
+		return nil
+	}, formats...)
+}
+
+// LookupImporter is Importer for IDs that name an object by something other than its state ID,
+// such as a line number standing in for a server-generated UID. Once the attributes from the ID
+// are set, `lookup` finds the object on the portal and sets the state ID, plus anything `read`
+// needs to find it again. A not-found error from lookup fails the import the same way an empty
+// read does.
+func LookupImporter(read schema.ReadContextFunc, lookup func(context.Context, *schema.ResourceData, any) error, formats ...string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+			importID := d.Id()
+
+			attrs, err := ParseImportID(importID, formats...)
+			if err != nil {
+				return nil, err
+			}
+			if err := setImportAttributes(d, attrs); err != nil {
+				return nil, fmt.Errorf("import ID %q: %w", importID, err)
+			}
+
+			if lookup != nil {
+				err := lookup(ctx, d, meta)
+				switch {
+				case tg.IsNotFound(err):
+					return nil, fmt.Errorf("nothing found for import ID %q (expected %s)", importID, describeImportFormats(formats))
+				case err != nil:
+					return nil, fmt.Errorf("import ID %q: %w", importID, err)
+				}
+			}
+
+			if err := diagsError(read(ctx, d, meta)); err != nil {
+				return nil, fmt.Errorf("import ID %q: %w", importID, err)
+			}
+			if d.Id() == "" {
+				return nil, fmt.Errorf("nothing found for import ID %q (expected %s)", importID, describeImportFormats(formats))
+			}
+
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// AttributeID returns an `Importer` ID function that uses the imported value of attr as the resource ID.
+func AttributeID(attr string) func(*schema.ResourceData) (string, error) {
+	return func(d *schema.ResourceData) (string, error) {
+		id, _ := d.Get(attr).(string)
+		if id == "" {
+			return "", fmt.Errorf("%s is empty", attr)
+		}
+		return id, nil
+	}
+}
+
+// diagsError folds the errors in diags into a single error.
+func diagsError(diags diag.Diagnostics) error {
+	var errs []error
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		if d.Detail != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", d.Summary, d.Detail))
+		} else {
+			errs = append(errs, errors.New(d.Summary))
+		}
+	}
+	return errors.Join(errs...)
+}
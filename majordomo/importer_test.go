@@ -0,0 +1,136 @@
+package majordomo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func TestParseImportID(t *testing.T) {
+	formats := []string{"node:{node_id}/{network_name}/{uid}", "cluster:{cluster_fqdn}/{network_name}/{uid}"}
+
+	attrs, err := ParseImportID("node:abc-123/vnet1/route-9", formats...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"node_id": "abc-123", "network_name": "vnet1", "uid": "route-9"}, attrs)
+
+	attrs, err = ParseImportID("cluster:edge.example.com/vnet1/route-9", formats...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"cluster_fqdn": "edge.example.com", "network_name": "vnet1", "uid": "route-9"}, attrs)
+
+	_, err = ParseImportID("edge.example.com/vnet1", formats...)
+	assert.EqualError(t, err, "unexpected import ID \"edge.example.com/vnet1\", expected `node:<node_id>/<network_name>/<uid>` or `cluster:<cluster_fqdn>/<network_name>/<uid>`")
+}
+
+func TestParseImportID_TrailingPartTakesTheRest(t *testing.T) {
+	attrs, err := ParseImportID("vnet1/10.0.0.0/24", "{network}/{network_cidr}")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"network": "vnet1", "network_cidr": "10.0.0.0/24"}, attrs)
+
+	_, err = ParseImportID("", "{uid}")
+	assert.Error(t, err)
+}
+
+func TestParseImportID_Patterns(t *testing.T) {
+	formats := []string{"{network}/{network_cidr:[^+]+}+{dest}", "{network}/{line_number:[0-9]+}", "{network}/{uid}"}
+
+	attrs, err := ParseImportID("vnet1/10.0.0.0/24+edge1", formats...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"network": "vnet1", "network_cidr": "10.0.0.0/24", "dest": "edge1"}, attrs)
+
+	attrs, err = ParseImportID("vnet1/20", formats...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"network": "vnet1", "line_number": "20"}, attrs)
+
+	attrs, err = ParseImportID("vnet1/rule-20", formats...)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"network": "vnet1", "uid": "rule-20"}, attrs)
+
+	_, err = ParseImportID("vnet1", formats...)
+	assert.EqualError(t, err, "unexpected import ID \"vnet1\", expected `<network>/<network_cidr>+<dest>` or `<network>/<line_number>` or `<network>/<uid>`")
+}
+
+func testImportResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"network":     {Type: schema.TypeString, Required: true},
+			"line_number": {Type: schema.TypeInt, Required: true},
+			"description": {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+func TestImporter(t *testing.T) {
+	read := func(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+		if d.Get("line_number").(int) != 10 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(d.Set("description", "found"))
+	}
+	importer := Importer(read, func(d *schema.ResourceData) (string, error) {
+		return d.Get("network").(string) + "-rule", nil
+	}, "{network}/{line_number}")
+
+	d := testImportResource().TestResourceData()
+	d.SetId("vnet1/10")
+	states, err := importer.StateContext(context.Background(), d, nil)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, "vnet1-rule", states[0].Id())
+	assert.Equal(t, 10, states[0].Get("line_number"))
+	assert.Equal(t, "found", states[0].Get("description"))
+
+	d = testImportResource().TestResourceData()
+	d.SetId("vnet1/20")
+	_, err = importer.StateContext(context.Background(), d, nil)
+	assert.EqualError(t, err, "nothing found for import ID \"vnet1/20\" (expected `<network>/<line_number>`)")
+
+	d = testImportResource().TestResourceData()
+	d.SetId("vnet1/ten")
+	_, err = importer.StateContext(context.Background(), d, nil)
+	assert.ErrorContains(t, err, `import ID "vnet1/ten": line_number`)
+}
+
+func TestLookupImporter(t *testing.T) {
+	read := func(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+		return diag.FromErr(d.Set("description", "rule "+d.Id()))
+	}
+	lookup := func(_ context.Context, d *schema.ResourceData, _ any) error {
+		if d.Get("line_number").(int) != 10 {
+			return &tg.NotFoundError{URL: "rule"}
+		}
+		d.SetId("uid-10")
+		return nil
+	}
+	importer := LookupImporter(read, lookup, "{network}/{line_number:[0-9]+}")
+
+	d := testImportResource().TestResourceData()
+	d.SetId("vnet1/10")
+	states, err := importer.StateContext(context.Background(), d, nil)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, "uid-10", states[0].Id())
+	assert.Equal(t, "rule uid-10", states[0].Get("description"))
+
+	d = testImportResource().TestResourceData()
+	d.SetId("vnet1/20")
+	_, err = importer.StateContext(context.Background(), d, nil)
+	assert.EqualError(t, err, "nothing found for import ID \"vnet1/20\" (expected `<network>/<line_number>`)")
+}
+
+func TestImporter_ReadError(t *testing.T) {
+	read := func(context.Context, *schema.ResourceData, any) diag.Diagnostics {
+		return diag.Errorf("portal unavailable")
+	}
+	importer := Importer(read, nil, "{network}/{line_number}")
+
+	d := testImportResource().TestResourceData()
+	d.SetId("vnet1/10")
+	_, err := importer.StateContext(context.Background(), d, nil)
+	assert.EqualError(t, err, `import ID "vnet1/10": portal unavailable`)
+}
@@ -0,0 +1,284 @@
+package majordomo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// ListDataSource reads a list endpoint into a plural data source, filtered by the standard
+// `name_regex`, `filter` and `tags` arguments.
+type ListDataSource[T any, H hcl.HCL[T]] struct {
+	description   string
+	indexURL      func(*tg.Client, *schema.ResourceData) string
+	params        map[string]*schema.Schema
+	attribute     string
+	element       map[string]*schema.Schema
+	remoteID      func(T) string
+	nameAttribute string
+	tags          func(T) map[string]string
+}
+
+type ListDataSourceArgs[T any, H hcl.HCL[T]] struct {
+	Description   string                                        // Description of the data source.
+	IndexURL      func(*tg.Client, *schema.ResourceData) string // IndexURL should return the URL for GET-ing the list, reading any `Params` from the data.
+	Params        map[string]*schema.Schema                     // Params are extra arguments `IndexURL` needs, like the network to list objects from. They're also decoded into H before each object is encoded.
+	Attribute     string                                        // Attribute names the list of matching objects, like `alarms`.
+	Element       map[string]*schema.Schema                     // Element is the schema of each listed object and must cover the `tf` tags of H. Its attributes are made computed.
+	RemoteID      func(T) string                                // RemoteID should return the ID of the `tg` resource. If set, matching IDs are also listed in `ids`.
+	NameAttribute string                                        // NameAttribute is the element attribute `name_regex` matches. Defaults to `name`; `name_regex` is left out if the element has no such attribute.
+	Tags          func(T) map[string]string                     // Tags should return the tags of the `tg` resource. If set, objects can be filtered by `tags`.
+}
+
+// NewListDataSource returns a new `ListDataSource`.
+func NewListDataSource[T any, H hcl.HCL[T]](args ListDataSourceArgs[T, H]) *ListDataSource[T, H] {
+	nameAttribute := args.NameAttribute
+	if nameAttribute == "" {
+		nameAttribute = "name"
+	}
+
+	return &ListDataSource[T, H]{
+		description:   args.Description,
+		indexURL:      args.IndexURL,
+		params:        args.Params,
+		attribute:     args.Attribute,
+		element:       ComputedSchema(args.Element),
+		remoteID:      args.RemoteID,
+		nameAttribute: nameAttribute,
+		tags:          args.Tags,
+	}
+}
+
+// ComputedSchema returns a copy of sm with every attribute, including nested ones, computed only,
+// so a resource or single data source schema can describe listed objects.
+func ComputedSchema(sm map[string]*schema.Schema) map[string]*schema.Schema {
+	out := make(map[string]*schema.Schema, len(sm))
+	for k, s := range sm {
+		c := &schema.Schema{
+			Type:        s.Type,
+			Description: s.Description,
+			Computed:    true,
+			Sensitive:   s.Sensitive,
+		}
+		switch elem := s.Elem.(type) {
+		case *schema.Resource:
+			c.Elem = &schema.Resource{Schema: ComputedSchema(elem.Schema)}
+		case *schema.Schema:
+			c.Elem = &schema.Schema{Type: elem.Type}
+		}
+		out[k] = c
+	}
+	return out
+}
+
+// Resource returns the data source's schema and read function.
+func (l *ListDataSource[T, H]) Resource() *schema.Resource {
+	sm := map[string]*schema.Schema{
+		"filter": {
+			Description: "Only include objects whose attribute `name` has one of `values`. A list attribute matches if any of its items does. Multiple filters must all match.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Description:  "Attribute name",
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice(l.filterable(), false),
+					},
+					"values": {
+						Description: "Accepted values",
+						Type:        schema.TypeList,
+						Required:    true,
+						MinItems:    1,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		l.attribute: {
+			Description: "Matching objects",
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Resource{Schema: l.element},
+		},
+	}
+	if _, ok := l.element[l.nameAttribute]; ok {
+		sm["name_regex"] = &schema.Schema{
+			Description:  fmt.Sprintf("Only include objects whose `%s` matches this regular expression", l.nameAttribute),
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsValidRegExp,
+		}
+	}
+	if l.tags != nil {
+		sm["tags"] = &schema.Schema{
+			Description: "Only include objects with all of these tags",
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+	if l.remoteID != nil {
+		sm["ids"] = &schema.Schema{
+			Description: "IDs of the matching objects",
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+	for k, s := range l.params {
+		sm[k] = s
+	}
+
+	return &schema.Resource{
+		Description: l.description,
+		ReadContext: l.Read,
+		Schema:      sm,
+	}
+}
+
+// filterable lists the element attributes `filter` can match on: primitives and lists of them.
+func (l *ListDataSource[T, H]) filterable() []string {
+	var names []string
+	for k, s := range l.element {
+		switch s.Type {
+		case schema.TypeString, schema.TypeInt, schema.TypeFloat, schema.TypeBool:
+			names = append(names, k)
+		case schema.TypeList, schema.TypeSet:
+			if _, ok := s.Elem.(*schema.Schema); ok {
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+type listFilter struct {
+	Name   string   `tf:"name"`
+	Values []string `tf:"values"`
+}
+
+type listFilters struct {
+	NameRegex string         `tf:"name_regex"`
+	Tags      map[string]any `tf:"tags"`
+	Filters   []listFilter   `tf:"filter"`
+}
+
+// Read calls `IndexURL` to list the objects, encodes each through H into the element schema and
+// keeps the ones matching every filter.
+func (l *ListDataSource[T, H]) Read(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+
+	filters, err := hcl.DecodeResourceData[listFilters](d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var nameRegex *regexp.Regexp
+	if filters.NameRegex != "" {
+		if nameRegex, err = regexp.Compile(filters.NameRegex); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	seed, err := hcl.DecodeResourceData[H](d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	url := l.indexURL(tgc, d)
+	upstream := make([]T, 0)
+	if err := tgc.Get(ctx, url, &upstream); err != nil {
+		return diag.FromErr(err)
+	}
+
+	elem := &schema.Resource{Schema: l.element}
+	items := make([]map[string]any, 0, len(upstream))
+	ids := make([]string, 0, len(upstream))
+	for _, t := range upstream {
+		if l.tags != nil && !matchTags(l.tags(t), filters.Tags) {
+			continue
+		}
+
+		ed := elem.Data(nil)
+		if err := hcl.EncodeResourceData(seed.UpdateFromTG(t), ed); err != nil {
+			return diag.FromErr(err)
+		}
+		item := make(map[string]any, len(l.element))
+		for k := range l.element {
+			item[k] = ed.Get(k)
+		}
+
+		if nameRegex != nil && !nameRegex.MatchString(fmt.Sprint(item[l.nameAttribute])) {
+			continue
+		}
+		if !matchFilters(item, filters.Filters) {
+			continue
+		}
+
+		items = append(items, item)
+		if l.remoteID != nil {
+			ids = append(ids, l.remoteID(t))
+		}
+	}
+
+	if err := d.Set(l.attribute, items); err != nil {
+		return diag.FromErr(err)
+	}
+	if l.remoteID != nil {
+		if err := d.Set("ids", ids); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.SetId(url)
+
+	return nil
+}
+
+func matchTags(tags map[string]string, want map[string]any) bool {
+	for k, v := range want {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func matchFilters(item map[string]any, filters []listFilter) bool {
+	for _, f := range filters {
+		if !matchFilter(item[f.Name], f.Values) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFilter reports whether v, or any item of it for lists and sets, is one of values.
+func matchFilter(v any, values []string) bool {
+	var candidates []any
+	switch v := v.(type) {
+	case []any:
+		candidates = v
+	case *schema.Set:
+		candidates = v.List()
+	default:
+		candidates = []any{v}
+	}
+
+	for _, c := range candidates {
+		for _, value := range values {
+			if fmt.Sprint(c) == value {
+				return true
+			}
+		}
+	}
+	return false
+}
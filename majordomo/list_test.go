@@ -0,0 +1,144 @@
+package majordomo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func listClient(t *testing.T) *tg.Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/org/mine", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"uid":"org-1","domain":"example.trustgrid.io"}`)
+	})
+	mux.HandleFunc("GET /api/v2/domain/example.trustgrid.io/network/vnet1/network-object", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"name":"web-1","cidr":"10.0.0.1/32"},{"name":"web-2","cidr":"10.0.0.2/32"},{"name":"db","cidr":"10.0.1.0/24"}]`)
+	})
+	mux.HandleFunc("GET /api/cluster", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"name":"edge","fqdn":"edge.example.com","health":"healthy","tags":{"env":"prod"}},{"name":"lab","fqdn":"lab.example.com","health":"degraded","tags":{"env":"dev"}}]`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	tgc, err := tg.NewClient(context.Background(), tg.ClientParams{
+		APIKey:     "key",
+		APISecret:  "secret",
+		BaseURL:    srv.URL + "/api/",
+		HTTPClient: srv.Client(),
+	})
+	require.NoError(t, err)
+	return tgc
+}
+
+func vnetObjects() *schema.Resource {
+	return NewListDataSource(ListDataSourceArgs[tg.VNetObject, hcl.VNetObject]{
+		IndexURL: func(tgc *tg.Client, d *schema.ResourceData) string {
+			return "/v2/domain/" + tgc.Domain + "/network/" + d.Get("network").(string) + "/network-object" //nolint: errcheck // just trusting TF validation here
+		},
+		Params:    map[string]*schema.Schema{"network": {Type: schema.TypeString, Required: true}},
+		Attribute: "objects",
+		Element: map[string]*schema.Schema{
+			"name":    {Type: schema.TypeString},
+			"cidr":    {Type: schema.TypeString},
+			"network": {Type: schema.TypeString},
+		},
+	}).Resource()
+}
+
+func clusters() *schema.Resource {
+	return NewListDataSource(ListDataSourceArgs[tg.Cluster, hcl.Cluster]{
+		IndexURL:  func(*tg.Client, *schema.ResourceData) string { return "/cluster" },
+		Attribute: "clusters",
+		Element: map[string]*schema.Schema{
+			"fqdn":   {Type: schema.TypeString, Required: true},
+			"name":   {Type: schema.TypeString},
+			"health": {Type: schema.TypeString},
+			"members": {Type: schema.TypeList, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"uid": {Type: schema.TypeString},
+			}}},
+		},
+		RemoteID: func(c tg.Cluster) string { return c.FQDN },
+		Tags:     func(c tg.Cluster) map[string]string { return c.Tags },
+	}).Resource()
+}
+
+func readList(t *testing.T, r *schema.Resource, tgc *tg.Client, args map[string]any) *schema.ResourceData {
+	t.Helper()
+
+	require.NoError(t, r.InternalValidate(nil, false))
+	d := schema.TestResourceDataRaw(t, r.Schema, args)
+	diags := r.ReadContext(context.Background(), d, tgc)
+	require.False(t, diags.HasError(), "%v", diags)
+	return d
+}
+
+func TestListDataSource_Schema(t *testing.T) {
+	r := clusters()
+
+	assert.True(t, r.Schema["clusters"].Computed)
+	elem, ok := r.Schema["clusters"].Elem.(*schema.Resource)
+	require.True(t, ok)
+	assert.True(t, elem.Schema["fqdn"].Computed)
+	assert.False(t, elem.Schema["fqdn"].Required)
+	assert.Contains(t, r.Schema, "name_regex")
+	assert.Contains(t, r.Schema, "tags")
+	assert.Contains(t, r.Schema, "ids")
+
+	r = vnetObjects()
+	assert.NotContains(t, r.Schema, "tags")
+	assert.NotContains(t, r.Schema, "ids")
+	assert.True(t, r.Schema["network"].Required)
+}
+
+func TestListDataSource_Read(t *testing.T) {
+	tgc := listClient(t)
+
+	d := readList(t, vnetObjects(), tgc, map[string]any{"network": "vnet1"})
+	assert.Equal(t, "/v2/domain/example.trustgrid.io/network/vnet1/network-object", d.Id())
+	assert.Equal(t, []any{
+		map[string]any{"name": "web-1", "cidr": "10.0.0.1/32", "network": "vnet1"},
+		map[string]any{"name": "web-2", "cidr": "10.0.0.2/32", "network": "vnet1"},
+		map[string]any{"name": "db", "cidr": "10.0.1.0/24", "network": "vnet1"},
+	}, d.Get("objects"))
+}
+
+func TestListDataSource_Filters(t *testing.T) {
+	tgc := listClient(t)
+
+	names := func(d *schema.ResourceData) []string {
+		var out []string
+		for _, o := range d.Get("objects").([]any) { //nolint: errcheck // just trusting TF validation here
+			out = append(out, o.(map[string]any)["name"].(string)) //nolint: errcheck // just trusting TF validation here
+		}
+		return out
+	}
+
+	d := readList(t, vnetObjects(), tgc, map[string]any{"network": "vnet1", "name_regex": "^web-"})
+	assert.Equal(t, []string{"web-1", "web-2"}, names(d))
+
+	d = readList(t, vnetObjects(), tgc, map[string]any{
+		"network": "vnet1",
+		"filter": []any{
+			map[string]any{"name": "cidr", "values": []any{"10.0.0.2/32", "10.0.1.0/24"}},
+			map[string]any{"name": "name", "values": []any{"web-2", "web-3"}},
+		},
+	})
+	assert.Equal(t, []string{"web-2"}, names(d))
+
+	d = readList(t, clusters(), tgc, map[string]any{"tags": map[string]any{"env": "prod"}})
+	assert.Equal(t, []any{"edge.example.com"}, d.Get("ids").(*schema.Set).List()) //nolint: errcheck // just trusting TF validation here
+	assert.Len(t, d.Get("clusters"), 1)
+
+	d = readList(t, clusters(), tgc, map[string]any{"filter": []any{map[string]any{"name": "health", "values": []any{"degraded"}}}})
+	assert.Equal(t, []any{"lab.example.com"}, d.Get("ids").(*schema.Set).List()) //nolint: errcheck // just trusting TF validation here
+}
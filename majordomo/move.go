@@ -0,0 +1,155 @@
+package majordomo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+)
+
+// StateMover moves the state of a resource of type `SourceType` to the resource it's registered
+// for, so a `moved` block (Terraform 1.8 or later) can change a resource's type without removing
+// and importing it.
+type StateMover struct {
+	SourceType string
+
+	move func(ctx context.Context, meta any, state map[string]any) (map[string]any, error)
+}
+
+// NewStateMover returns a StateMover for state of sourceType. The state is decoded into Old with
+// `tf` tags and passed to move along with its ID and the configured provider's meta. move returns
+// the ID and attributes of the moved resource.
+func NewStateMover[Old any, New any](sourceType string, move func(ctx context.Context, meta any, id string, old Old) (string, New, error)) StateMover {
+	return StateMover{
+		SourceType: sourceType,
+		move: func(ctx context.Context, meta any, state map[string]any) (map[string]any, error) {
+			old, err := hcl.DecodeState[Old](state)
+			if err != nil {
+				return nil, fmt.Errorf("decoding state: %w", err)
+			}
+
+			id, _ := state["id"].(string)
+			id, moved, err := move(ctx, meta, id, old)
+			if err != nil {
+				return nil, err
+			}
+
+			out, err := hcl.EncodeState(moved)
+			if err != nil {
+				return nil, fmt.Errorf("encoding state: %w", err)
+			}
+			out["id"] = id
+			return out, nil
+		},
+	}
+}
+
+// providerServer is the plugin SDK's provider server with support for moving state between
+// resource types, which the SDK doesn't implement.
+type providerServer struct {
+	*schema.GRPCProviderServer
+
+	provider *schema.Provider
+	movers   map[string][]StateMover
+}
+
+// ProviderServer returns a provider server for p that moves state into the resources named in
+// movers with their StateMovers. Serve it with `plugin.ServeOpts.GRPCProviderFunc`.
+func ProviderServer(p *schema.Provider, movers map[string][]StateMover) tfprotov5.ProviderServer {
+	return &providerServer{
+		GRPCProviderServer: schema.NewGRPCProviderServer(p),
+		provider:           p,
+		movers:             movers,
+	}
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.GRPCProviderServer.GetMetadata(ctx, req)
+	if resp != nil && resp.ServerCapabilities != nil {
+		resp.ServerCapabilities.MoveResourceState = true
+	}
+	return resp, err
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.GRPCProviderServer.GetProviderSchema(ctx, req)
+	if resp != nil && resp.ServerCapabilities != nil {
+		resp.ServerCapabilities.MoveResourceState = true
+	}
+	return resp, err
+}
+
+func (s *providerServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("MoveResourceState request is nil")
+	}
+
+	moved, err := s.move(ctx, req)
+	if err != nil {
+		return &tfprotov5.MoveResourceStateResponse{
+			Diagnostics: []*tfprotov5.Diagnostic{{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("moving %s to %s", req.SourceTypeName, req.TargetTypeName),
+				Detail:   err.Error(),
+			}},
+		}, nil
+	}
+
+	target := s.provider.ResourcesMap[req.TargetTypeName]
+
+	// Let the SDK normalize the moved state against the target's schema, the same way it does state
+	// written by the target's current version.
+	upgraded, err := s.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: req.TargetTypeName,
+		Version:  int64(target.SchemaVersion),
+		RawState: &tfprotov5.RawState{JSON: moved},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfprotov5.MoveResourceStateResponse{
+		TargetState: upgraded.UpgradedState,
+		Diagnostics: upgraded.Diagnostics,
+	}, nil
+}
+
+// move runs the StateMover registered for the request, returning the moved state as JSON.
+func (s *providerServer) move(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) ([]byte, error) {
+	var mover *StateMover
+	for i, m := range s.movers[req.TargetTypeName] {
+		if m.SourceType == req.SourceTypeName {
+			mover = &s.movers[req.TargetTypeName][i]
+			break
+		}
+	}
+	if mover == nil {
+		return nil, fmt.Errorf("%s state can't be moved to %s", req.SourceTypeName, req.TargetTypeName)
+	}
+
+	source, ok := s.provider.ResourcesMap[req.SourceTypeName]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %s", req.SourceTypeName)
+	}
+	if req.SourceSchemaVersion != int64(source.SchemaVersion) {
+		return nil, fmt.Errorf("%s state is at schema version %d, expected %d; apply with this provider version before moving it", req.SourceTypeName, req.SourceSchemaVersion, source.SchemaVersion)
+	}
+	if req.SourceState == nil || len(req.SourceState.JSON) == 0 {
+		return nil, fmt.Errorf("%s state has no JSON to move", req.SourceTypeName)
+	}
+
+	var state map[string]any
+	if err := json.Unmarshal(req.SourceState.JSON, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s state: %w", req.SourceTypeName, err)
+	}
+
+	moved, err := mover.move(ctx, s.provider.Meta(), state)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(moved)
+}
@@ -0,0 +1,122 @@
+package majordomo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+)
+
+// moveProbeServer serves probeProvider with a tg_legacy_probe resource, whose state moves to
+// tg_probe.
+func moveProbeServer(t *testing.T) tfprotov5.ProviderServer {
+	t.Helper()
+
+	p := probeProvider()
+	p.ResourcesMap["tg_legacy_probe"] = &schema.Resource{Schema: hcl.Schema[probeV0](nil)}
+	require.NoError(t, p.InternalValidate())
+
+	return ProviderServer(p, map[string][]StateMover{
+		"tg_probe": {
+			NewStateMover("tg_legacy_probe", func(ctx context.Context, _ any, id string, old probeV0) (string, probe, error) {
+				moved, err := upgradeProbeV0(ctx, old)
+				return id, moved, err
+			}),
+		},
+	})
+}
+
+func TestProviderServer_Capabilities(t *testing.T) {
+	srv := moveProbeServer(t)
+
+	schemaResp, err := srv.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	assert.True(t, schemaResp.ServerCapabilities.MoveResourceState)
+
+	metaResp, err := srv.GetMetadata(context.Background(), &tfprotov5.GetMetadataRequest{})
+	require.NoError(t, err)
+	assert.True(t, metaResp.ServerCapabilities.MoveResourceState)
+}
+
+func TestProviderServer_MoveResourceState(t *testing.T) {
+	srv := moveProbeServer(t)
+
+	resp, err := srv.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "tg_legacy_probe",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id":"n1/web","node_id":"n1","name":"web","target":"10.0.0.1:8080","tags":"prod","interval":30}`)},
+		TargetTypeName: "tg_probe",
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Diagnostics)
+
+	ty := probeProvider().ResourcesMap["tg_probe"].CoreConfigSchema().ImpliedType()
+	v, err := msgpack.Unmarshal(resp.TargetState.MsgPack, ty)
+	require.NoError(t, err)
+	js, err := ctyjson.Marshal(v, ty)
+	require.NoError(t, err)
+
+	var state map[string]any
+	require.NoError(t, json.Unmarshal(js, &state))
+	assert.Equal(t, map[string]any{
+		"id":               "n1/web",
+		"node_id":          "n1",
+		"name":             "web",
+		"target":           []any{map[string]any{"host": "10.0.0.1", "port": float64(8080)}},
+		"tags":             []any{"prod"},
+		"interval_seconds": float64(30),
+	}, state)
+}
+
+func TestProviderServer_MoveResourceStateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    *tfprotov5.MoveResourceStateRequest
+		detail string
+	}{
+		{
+			name: "no mover",
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceTypeName: "tg_probe",
+				SourceState:    &tfprotov5.RawState{JSON: []byte(`{}`)},
+				TargetTypeName: "tg_legacy_probe",
+			},
+			detail: "tg_probe state can't be moved to tg_legacy_probe",
+		},
+		{
+			name: "old schema version",
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceTypeName:      "tg_legacy_probe",
+				SourceSchemaVersion: 2,
+				SourceState:         &tfprotov5.RawState{JSON: []byte(`{}`)},
+				TargetTypeName:      "tg_probe",
+			},
+			detail: "tg_legacy_probe state is at schema version 2, expected 0; apply with this provider version before moving it",
+		},
+		{
+			name: "move fails",
+			req: &tfprotov5.MoveResourceStateRequest{
+				SourceTypeName: "tg_legacy_probe",
+				SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id":"n1/db","node_id":"n1","name":"db","target":"10.0.0.2"}`)},
+				TargetTypeName: "tg_probe",
+			},
+			detail: `target "10.0.0.2" has no port`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := moveProbeServer(t).MoveResourceState(context.Background(), tt.req)
+			require.NoError(t, err)
+			require.Len(t, resp.Diagnostics, 1)
+			assert.Equal(t, "moving "+tt.req.SourceTypeName+" to "+tt.req.TargetTypeName, resp.Diagnostics[0].Summary)
+			assert.Equal(t, tt.detail, resp.Diagnostics[0].Detail)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package majordomo
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -32,6 +33,10 @@ type Resource[T any, H hcl.HCL[T]] struct {
 	indexURL      func(H) string
 	id            func(H) string
 	remoteID      func(T) string
+	lockTarget    func(H) string
+	importID      []string
+	converged     func(H, T) bool
+	poller        tg.Poller
 }
 
 type ResourceArgs[T any, H hcl.HCL[T]] struct {
@@ -46,6 +51,9 @@ type ResourceArgs[T any, H hcl.HCL[T]] struct {
 	IndexURL      func(H) string                 // IndexURL should return the URL for GET-ing a list of resources. If this and RemoteID are provided and GetURL is not, `Read` will attempt to call `Index` and search for the resource.
 	RemoteID      func(T) string                 // RemoteID should return the ID of `tg` resource from the remote API.
 	ID            func(H) string                 // ID should return the ID of the `hcl` resource.
+	LockTarget    func(H) string                 // LockTarget should return the `tg` lock target writes are serialized on. If not set, it's derived from the write URL with `tg.TargetOf`.
+	ImportID      []string                       // ImportID lists the import ID formats, like `cluster:{cluster_fqdn}/{uid}`, naming the attributes each part is written to. If not set, the resource can't be imported.
+	Converged     func(H, T) bool                // Converged should report whether the remote resource reflects the written one. If set, `Create` and `Update` poll until it does, bounded by the resource's `Timeouts`.
 }
 
 // NewResource returns a new `Resource`.
@@ -62,9 +70,38 @@ func NewResource[T any, H hcl.HCL[T]](args ResourceArgs[T, H]) *Resource[T, H] {
 		indexURL:      args.IndexURL,
 		remoteID:      args.RemoteID,
 		id:            args.ID,
+		lockTarget:    args.LockTarget,
+		importID:      args.ImportID,
+		converged:     args.Converged,
 	}
 }
 
+// Importer returns an importer for the formats in `ImportID`, or nil if it isn't set.
+// The state is seeded from the import ID and checked with `Read`.
+func (r *Resource[T, H]) Importer() *schema.ResourceImporter {
+	if len(r.importID) == 0 {
+		return nil
+	}
+
+	id := func(d *schema.ResourceData) (string, error) {
+		tf, err := hcl.DecodeResourceData[H](d)
+		if err != nil {
+			return "", err
+		}
+		return r.id(tf), nil
+	}
+
+	return Importer(r.Read, id, r.importID...)
+}
+
+// lock takes the client lock for the target the resource writes to.
+func (r *Resource[T, H]) lock(tgc *tg.Client, tf H, url string) func() {
+	if r.lockTarget != nil {
+		return tgc.LockTarget(r.lockTarget(tf))
+	}
+	return tgc.LockTarget(tg.TargetOf(url))
+}
+
 // Create calls the `CreateURL` function to get the URL for POST-ing the resource. If `CreateURL` is not set, calls `Update`.
 // HCL information is decoded from the `ResourceData` and marshaled to `tg` type before POST-ing.
 func (r *Resource[T, H]) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -81,10 +118,11 @@ func (r *Resource[T, H]) Create(ctx context.Context, d *schema.ResourceData, met
 
 	tg := tf.ToTG()
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	url := r.createURL(tf)
+	unlock := r.lock(tgc, tf, url)
+	defer unlock()
 
-	reply, err := tgc.Post(ctx, r.createURL(tf), tg)
+	reply, err := tgc.Post(ctx, url, tg)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -111,6 +149,10 @@ func (r *Resource[T, H]) Create(ctx context.Context, d *schema.ResourceData, met
 			return diag.FromErr(err)
 		}
 	}
+
+	if err := r.waitForConvergence(ctx, tf, meta); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
@@ -131,10 +173,11 @@ func (r *Resource[T, H]) Update(ctx context.Context, d *schema.ResourceData, met
 
 	tg := tf.ToTG()
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	url := r.updateURL(tf)
+	unlock := r.lock(tgc, tf, url)
+	defer unlock()
 
-	reply, err := tgc.Put(ctx, r.updateURL(tf), tg)
+	reply, err := tgc.Put(ctx, url, tg)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -156,6 +199,9 @@ func (r *Resource[T, H]) Update(ctx context.Context, d *schema.ResourceData, met
 		d.SetId(r.id(tf))
 	}
 
+	if err := r.waitForConvergence(ctx, tf, meta); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
@@ -171,10 +217,11 @@ func (r *Resource[T, H]) Delete(ctx context.Context, d *schema.ResourceData, met
 
 	tg := tf.ToTG()
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	url := r.deleteURL(tf)
+	unlock := r.lock(tgc, tf, url)
+	defer unlock()
 
-	if err := tgc.Delete(ctx, r.deleteURL(tf), tg); err != nil {
+	if err := tgc.Delete(ctx, url, tg); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -229,6 +276,35 @@ func (r *Resource[T, H]) Read(ctx context.Context, d *schema.ResourceData, meta
 	return nil
 }
 
+// waitForConvergence polls the resource until `Converged` reports the write has taken effect.
+// It returns immediately if `Converged` isn't set. Every check reads from the portal, since a
+// cached node or cluster document would never change.
+func (r *Resource[T, H]) waitForConvergence(ctx context.Context, tf H, meta any) error {
+	if r.converged == nil {
+		return nil
+	}
+
+	err := r.poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		ctx = tg.WithoutCache(ctx)
+		var t T
+		var ok bool
+		var err error
+		if r.getURL != nil {
+			t, ok, err = r.read(ctx, tf, meta)
+		} else {
+			t, ok, err = r.index(ctx, tf, meta)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+		return r.converged(tf, t), nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for %s to converge: %w", r.id(tf), err)
+	}
+	return nil
+}
+
 func (r *Resource[T, H]) index(ctx context.Context, tf H, meta any) (T, bool, error) {
 	tgc := tg.GetClient(meta)
 
@@ -256,9 +332,8 @@ func (r *Resource[T, H]) read(ctx context.Context, tf H, meta any) (T, bool, err
 	if r.getFromNode != nil {
 		var n tg.Node
 		err := tgc.Get(ctx, r.getURL(tf), &n)
-		var nferr *tg.NotFoundError
 		switch {
-		case errors.As(err, &nferr):
+		case tg.IsNotFound(err):
 			return out, false, nil
 		case err != nil:
 			return out, false, err
@@ -268,9 +343,8 @@ func (r *Resource[T, H]) read(ctx context.Context, tf H, meta any) (T, bool, err
 	}
 
 	err := tgc.Get(ctx, r.getURL(tf), &out)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		return out, false, nil
 	case err != nil:
 		return out, false, err
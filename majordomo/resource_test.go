@@ -0,0 +1,101 @@
+package majordomo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// nodeStateResource is a node state resource that waits for the portal to report the written state.
+func nodeStateResource(t *testing.T, srv *httptest.Server) (*schema.Resource, *tg.Client) {
+	t.Helper()
+
+	md := NewResource(ResourceArgs[tg.NodeState, hcl.Node]{
+		UpdateURL: func(n hcl.Node) string { return "/node/" + n.UID },
+		GetURL:    func(n hcl.Node) string { return "/node/" + n.UID },
+		ID:        func(n hcl.Node) string { return n.UID },
+		Converged: func(n hcl.Node, s tg.NodeState) bool { return s.State == n.ToTG().State },
+	})
+	md.poller = tg.Poller{Interval: time.Millisecond}
+
+	tgc, err := tg.NewClient(context.Background(), tg.ClientParams{
+		APIKey:     "key",
+		APISecret:  "secret",
+		BaseURL:    srv.URL + "/api/",
+		HTTPClient: srv.Client(),
+		CacheTTL:   tg.DefaultCacheTTL,
+	})
+	require.NoError(t, err)
+
+	return &schema.Resource{
+		CreateContext: md.Create,
+		UpdateContext: md.Update,
+		ReadContext:   md.Read,
+		Schema: map[string]*schema.Schema{
+			"node_id": {Type: schema.TypeString, Required: true},
+			"enabled": {Type: schema.TypeBool, Required: true},
+		},
+	}, tgc
+}
+
+func TestResource_WaitsForConvergence(t *testing.T) {
+	var reads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/org/mine", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"uid":"org-1","domain":"example.trustgrid.io"}`)
+	})
+	mux.HandleFunc("PUT /api/node/n1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{}`)
+	})
+	mux.HandleFunc("GET /api/node/n1", func(w http.ResponseWriter, _ *http.Request) {
+		if reads.Add(1) < 3 {
+			_, _ = io.WriteString(w, `{"state":"INACTIVE"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"state":"ACTIVE"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	r, tgc := nodeStateResource(t, srv)
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node_id": "n1", "enabled": true})
+
+	diags := r.CreateContext(t.Context(), d, tgc)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, int32(3), reads.Load())
+	assert.Equal(t, "n1", d.Id())
+}
+
+func TestResource_ConvergenceTimesOut(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/org/mine", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"uid":"org-1","domain":"example.trustgrid.io"}`)
+	})
+	mux.HandleFunc("PUT /api/node/n1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{}`)
+	})
+	mux.HandleFunc("GET /api/node/n1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"state":"INACTIVE"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	r, tgc := nodeStateResource(t, srv)
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"node_id": "n1", "enabled": true})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	diags := r.UpdateContext(ctx, d, tgc)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "waiting for n1 to converge: timed out waiting for condition")
+}
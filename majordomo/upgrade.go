@@ -0,0 +1,41 @@
+package majordomo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+)
+
+// StateUpgrader upgrades state written with version `version` of a resource's schema, which was
+// prior, to the next version. The state is decoded into Old with `tf` tags, passed to upgrade, and
+// New is encoded back, so upgrades deal in typed models rather than raw maps.
+//
+// Register it in the resource's StateUpgraders and bump its SchemaVersion past version:
+//
+//	SchemaVersion: 1,
+//	StateUpgraders: []schema.StateUpgrader{
+//		majordomo.StateUpgrader(0, resourceV0Schema(), upgradeResourceV0),
+//	},
+//
+// Attributes Old has no field for, like `id`, are carried over unchanged.
+func StateUpgrader[Old any, New any](version int, prior map[string]*schema.Schema, upgrade func(ctx context.Context, old Old) (New, error)) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    (&schema.Resource{Schema: prior}).CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, state map[string]any, _ any) (map[string]any, error) {
+			if state == nil {
+				return nil, nil
+			}
+
+			upgraded, err := hcl.UpgradeState(state, func(old Old) (New, error) {
+				return upgrade(ctx, old)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("upgrading state from schema version %d: %w", version, err)
+			}
+			return upgraded, nil
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package majordomo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+)
+
+// probeV0 is a resource as it was first released, with the target as `host:port` and tags as a
+// comma separated list.
+type probeV0 struct {
+	NodeID   string `tf:"node_id" schema:"required"`
+	Name     string `tf:"name" schema:"required"`
+	Target   string `tf:"target" schema:"required"`
+	Tags     string `tf:"tags" schema:"optional"`
+	Interval int    `tf:"interval" schema:"optional"`
+}
+
+type probeTarget struct {
+	Host string `tf:"host" schema:"required"`
+	Port int    `tf:"port" schema:"required"`
+}
+
+// probe is the current version of the resource, with the target as a block, tags as a list and the
+// interval renamed.
+type probe struct {
+	NodeID          string       `tf:"node_id" schema:"required"`
+	Name            string       `tf:"name" schema:"required"`
+	Target          *probeTarget `tf:"target" schema:"required"`
+	Tags            []string     `tf:"tags" schema:"optional"`
+	IntervalSeconds int          `tf:"interval_seconds" schema:"optional"`
+}
+
+func upgradeProbeV0(_ context.Context, old probeV0) (probe, error) {
+	host, port, ok := strings.Cut(old.Target, ":")
+	if !ok {
+		return probe{}, fmt.Errorf("target %q has no port", old.Target)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return probe{}, fmt.Errorf("target %q has an invalid port: %w", old.Target, err)
+	}
+
+	var tags []string
+	if old.Tags != "" {
+		tags = strings.Split(old.Tags, ",")
+	}
+
+	return probe{
+		NodeID:          old.NodeID,
+		Name:            old.Name,
+		Target:          &probeTarget{Host: host, Port: p},
+		Tags:            tags,
+		IntervalSeconds: old.Interval,
+	}, nil
+}
+
+func probeProvider() *schema.Provider {
+	return &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"tg_probe": {
+				Schema:        hcl.Schema[probe](nil),
+				SchemaVersion: 1,
+				StateUpgraders: []schema.StateUpgrader{
+					StateUpgrader(0, hcl.Schema[probeV0](nil), upgradeProbeV0),
+				},
+			},
+		},
+	}
+}
+
+// upgradeFixture upgrades the recorded version 0 state in testdata/state the way Terraform would.
+func upgradeFixture(t *testing.T, fixture string) (map[string]any, *tfprotov5.UpgradeResourceStateResponse) {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "state", fixture))
+	require.NoError(t, err)
+
+	p := probeProvider()
+	require.NoError(t, p.InternalValidate())
+
+	resp, err := schema.NewGRPCProviderServer(p).UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "tg_probe",
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: raw},
+	})
+	require.NoError(t, err)
+	if resp.UpgradedState == nil {
+		return nil, resp
+	}
+
+	ty := p.ResourcesMap["tg_probe"].CoreConfigSchema().ImpliedType()
+	v, err := msgpack.Unmarshal(resp.UpgradedState.MsgPack, ty)
+	require.NoError(t, err)
+	js, err := ctyjson.Marshal(v, ty)
+	require.NoError(t, err)
+
+	var state map[string]any
+	require.NoError(t, json.Unmarshal(js, &state))
+	return state, resp
+}
+
+func TestStateUpgrader(t *testing.T) {
+	state, resp := upgradeFixture(t, "probe_v0.json")
+	require.Empty(t, resp.Diagnostics)
+
+	assert.Equal(t, map[string]any{
+		"id":               "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d/web",
+		"node_id":          "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d",
+		"name":             "web",
+		"target":           []any{map[string]any{"host": "10.0.0.1", "port": float64(8080)}},
+		"tags":             []any{"prod", "edge"},
+		"interval_seconds": float64(30),
+	}, state)
+}
+
+func TestStateUpgrader_Error(t *testing.T) {
+	_, resp := upgradeFixture(t, "probe_v0_bad_target.json")
+	require.Len(t, resp.Diagnostics, 1)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+	assert.Equal(t, `upgrading state from schema version 0: target "10.0.0.2" has no port`, resp.Diagnostics[0].Summary)
+}
+
+func TestStateUpgrader_NilState(t *testing.T) {
+	upgrader := StateUpgrader(0, hcl.Schema[probeV0](nil), upgradeProbeV0)
+	assert.Equal(t, 0, upgrader.Version)
+
+	state, err := upgrader.Upgrade(context.Background(), nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
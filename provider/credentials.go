@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultProfile = "default"
+	defaultAPIHost = "api.trustgrid.io"
+)
+
+// profile holds the settings of one named section of the shared credentials file.
+// Keys use the same names as the provider arguments.
+type profile map[string]string
+
+// defaultCredentialsFile returns ~/.trustgrid/credentials.
+func defaultCredentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".trustgrid", "credentials"), nil
+}
+
+// parseCredentials reads an INI-style credentials file:
+//
+//	[default]
+//	api_key_id     = ...
+//	api_key_secret = ...
+//
+//	[staging]
+//	api_host = "api.staging.example.com"
+//	api_jwt  = ...
+//
+// Values may be quoted, so simple TOML files with string values parse the same way.
+// Lines starting with # or ; are comments.
+func parseCredentials(r io.Reader) (map[string]profile, error) {
+	profiles := make(map[string]profile)
+	var current profile
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.Trim(strings.TrimSpace(line[1:len(line)-1]), `"`)
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", n)
+			}
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = make(profile)
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a [profile] section", n)
+		}
+		current[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+
+	return profiles, scanner.Err()
+}
+
+func unquote(v string) string {
+	if len(v) >= 2 && (v[0] == '"' && v[len(v)-1] == '"' || v[0] == '\'' && v[len(v)-1] == '\'') {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// loadProfile returns the named profile from the credentials file. A missing file or profile
+// is only an error when the user asked for it explicitly; otherwise an empty profile is returned.
+func loadProfile(path string, name string) (profile, error) {
+	explicit := path != "" || name != ""
+
+	if name == "" {
+		name = defaultProfile
+	}
+	if path == "" {
+		var err error
+		if path, err = defaultCredentialsFile(); err != nil {
+			return nil, fmt.Errorf("error locating shared credentials file: %w", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && !explicit:
+		return profile{}, nil
+	case err != nil:
+		return nil, fmt.Errorf("error opening shared credentials file: %w", err)
+	}
+	defer f.Close()
+
+	profiles, err := parseCredentials(f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing shared credentials file %s: %w", path, err)
+	}
+
+	p, ok := profiles[name]
+	switch {
+	case !ok && explicit:
+		return nil, fmt.Errorf("profile %q not found in shared credentials file %s", name, path)
+	case !ok:
+		return profile{}, nil
+	}
+
+	return p, nil
+}
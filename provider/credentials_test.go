@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCredentials = `
+# personal org
+[default]
+api_key_id     = key
+api_key_secret = "secret"
+
+; staging uses a JWT
+[staging]
+api_host = 'api.staging.example.com'
+api_jwt  = jwt
+org_id   = org-2
+`
+
+func TestParseCredentials(t *testing.T) {
+	profiles, err := parseCredentials(strings.NewReader(testCredentials))
+	require.NoError(t, err)
+
+	assert.Equal(t, profile{"api_key_id": "key", "api_key_secret": "secret"}, profiles["default"])
+	assert.Equal(t, profile{"api_host": "api.staging.example.com", "api_jwt": "jwt", "org_id": "org-2"}, profiles["staging"])
+}
+
+func TestParseCredentials_Errors(t *testing.T) {
+	_, err := parseCredentials(strings.NewReader("api_key_id = key\n"))
+	assert.ErrorContains(t, err, "outside of a [profile] section")
+
+	_, err = parseCredentials(strings.NewReader("[default]\nnonsense\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(path, []byte(testCredentials), 0o600))
+
+	p, err := loadProfile(path, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt", p["api_jwt"])
+
+	p, err = loadProfile(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "key", p["api_key_id"])
+
+	_, err = loadProfile(path, "prod")
+	assert.ErrorContains(t, err, `profile "prod" not found`)
+
+	_, err = loadProfile(filepath.Join(t.TempDir(), "missing"), "")
+	assert.Error(t, err, "an explicit file must exist")
+
+	t.Setenv("HOME", t.TempDir())
+	p, err = loadProfile("", "")
+	require.NoError(t, err, "the default file is optional")
+	assert.Empty(t, p)
+}
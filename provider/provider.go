@@ -2,10 +2,18 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/datasource"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/resource"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
@@ -33,10 +41,17 @@ func New(version string) func() *schema.Provider {
 				},
 				"api_host": {
 					Type:        schema.TypeString,
-					Description: "Trustgrid Portal endpoint. Used for development.",
+					Description: "Trustgrid Portal endpoint. Used for development. Defaults to `api.trustgrid.io`.",
 					Optional:    true,
 					Sensitive:   false,
-					DefaultFunc: schema.EnvDefaultFunc("TG_API_HOST", "api.trustgrid.io"),
+					DefaultFunc: schema.EnvDefaultFunc("TG_API_HOST", nil),
+				},
+				"api_base_url": {
+					Type:         schema.TypeString,
+					Description:  "Full Trustgrid Portal URL, including scheme and any path prefix (e.g. `http://localhost:8080/api`). Overrides `api_host` when set. Will use the `TG_API_BASE_URL` environment variable if not set.",
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_API_BASE_URL", nil),
+					ValidateFunc: validation.IsURLWithScheme([]string{"http", "https"}),
 				},
 				"api_jwt": {
 					Type:        schema.TypeString,
@@ -45,6 +60,27 @@ func New(version string) func() *schema.Provider {
 					Sensitive:   true,
 					DefaultFunc: schema.EnvDefaultFunc("TG_JWT", nil),
 				},
+				"client_id": {
+					Type:         schema.TypeString,
+					Description:  "OAuth client ID, such as the `client_id` of a `tg_serviceuser`. Exchanged together with `client_secret` at `token_path` for short-lived JWTs that are refreshed automatically. Takes precedence over `api_jwt` and the API key pair. Will use the `TG_CLIENT_ID` environment variable if not set.",
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_CLIENT_ID", nil),
+					RequiredWith: []string{"client_secret"},
+				},
+				"client_secret": {
+					Type:         schema.TypeString,
+					Description:  "OAuth client secret, such as the `secret` of a `tg_serviceuser`. Will use the `TG_CLIENT_SECRET` environment variable if not set.",
+					Optional:     true,
+					Sensitive:    true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_CLIENT_SECRET", nil),
+					RequiredWith: []string{"client_id"},
+				},
+				"token_path": {
+					Type:        schema.TypeString,
+					Description: "Path of the portal endpoint that exchanges `client_id` and `client_secret` for a JWT, relative to the portal URL. Required with `client_id`. Will use the `TG_TOKEN_PATH` environment variable if not set.",
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("TG_TOKEN_PATH", nil),
+				},
 				"org_id": {
 					Type:        schema.TypeString,
 					Description: "Trustgrid Org ID. If provided and the credentials aren't for that org, the provider will fail early.",
@@ -52,35 +88,116 @@ func New(version string) func() *schema.Provider {
 					Sensitive:   false,
 					DefaultFunc: schema.EnvDefaultFunc("TG_ORG_ID", nil),
 				},
+				"profile": {
+					Type:        schema.TypeString,
+					Description: "Name of the profile in the shared credentials file to read settings from. Will use the `TG_PROFILE` environment variable if not set, then `default`. Settings given as provider arguments or `TG_*` environment variables take precedence over the profile.",
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("TG_PROFILE", nil),
+				},
+				"shared_credentials_file": {
+					Type:        schema.TypeString,
+					Description: "Path to the shared credentials file. Will use the `TG_SHARED_CREDENTIALS_FILE` environment variable if not set, then `~/.trustgrid/credentials`.",
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("TG_SHARED_CREDENTIALS_FILE", nil),
+				},
+				"proxy_url": {
+					Type:         schema.TypeString,
+					Description:  "URL of the HTTP(S) proxy used to reach the portal. When not set, the standard `HTTPS_PROXY`/`NO_PROXY` environment variables apply.",
+					Optional:     true,
+					ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				},
+				"ca_cert_file": {
+					Type:        schema.TypeString,
+					Description: "Path to a PEM-encoded CA bundle trusted in addition to the system roots, e.g. for a TLS-intercepting proxy. Will use the `TG_CA_CERT_FILE` environment variable if not set.",
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("TG_CA_CERT_FILE", nil),
+				},
+				"client_cert": {
+					Type:         schema.TypeString,
+					Description:  "PEM-encoded client certificate, or a path to one, for mutual TLS. Requires `client_key`.",
+					Optional:     true,
+					RequiredWith: []string{"client_key"},
+				},
+				"client_key": {
+					Type:         schema.TypeString,
+					Description:  "PEM-encoded client private key, or a path to one, for mutual TLS. Requires `client_cert`.",
+					Optional:     true,
+					Sensitive:    true,
+					RequiredWith: []string{"client_cert"},
+				},
+				"defer_network_commits": {
+					Type:        schema.TypeBool,
+					Description: "Stage virtual network changes without committing them, so a `tg_virtual_network_commit` resource can validate and commit them all at once. Deletions are still committed as they're staged. Will use the `TG_DEFER_NETWORK_COMMITS` environment variable if not set.",
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("TG_DEFER_NETWORK_COMMITS", false),
+				},
+				"insecure_skip_verify": {
+					Type:        schema.TypeBool,
+					Description: "Skip verification of the portal's TLS certificate. Only use this for development.",
+					Optional:    true,
+				},
+				"max_retries": {
+					Type:         schema.TypeInt,
+					Description:  "Maximum number of times a throttled (429) or unavailable (502/503/504) portal request is retried. GET, PUT and DELETE are always retried; POST is only retried when the portal indicates the request wasn't processed. Set to 0 to disable retries. Will use the `TG_MAX_RETRIES` environment variable if not set.",
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_MAX_RETRIES", tg.DefaultMaxRetries),
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"requests_per_second": {
+					Type:         schema.TypeFloat,
+					Description:  "Maximum number of portal requests per second, retries included. Requests over the limit wait their turn. Set to 0 to disable limiting. Will use the `TG_REQUESTS_PER_SECOND` environment variable if not set.",
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_REQUESTS_PER_SECOND", 0.0),
+					ValidateFunc: validation.FloatAtLeast(0),
+				},
+				"burst": {
+					Type:         schema.TypeInt,
+					Description:  "Number of portal requests that may be sent at once before `requests_per_second` applies. Defaults to `requests_per_second`, rounded up. Will use the `TG_BURST` environment variable if not set.",
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_BURST", 0),
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"retry_max_wait": {
+					Type:         schema.TypeInt,
+					Description:  "Maximum number of seconds to wait between retries, including waits requested by the portal's `Retry-After` header. Will use the `TG_RETRY_MAX_WAIT` environment variable if not set.",
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("TG_RETRY_MAX_WAIT", int(tg.DefaultRetryMaxWait/time.Second)),
+					ValidateFunc: validation.IntAtLeast(1),
+				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				"tg_alarm":            datasource.Alarm(),
-				"tg_alarm_channel":    datasource.AlarmChannel(),
-				"tg_app":              datasource.App(),
-				"tg_cert":             datasource.Cert(),
-				"tg_cluster":          datasource.Cluster(),
-				"tg_device_info":      datasource.Device(),
-				"tg_group":            datasource.Group(),
-				"tg_idp":              datasource.IDP(),
-				"tg_network_config":   datasource.NetworkConfig(),
-				"tg_node":             datasource.Node(),
-				"tg_node_iface_names": datasource.NodeIfaceNames(),
-				"tg_nodes":            datasource.Nodes(),
-				"tg_org":              datasource.Org(),
-				"tg_kvm_image":        datasource.KVMImage(),
-				"tg_kvm_volume":       datasource.KVMVolume(),
-				"tg_policy":           datasource.Policy(),
-				"tg_policies":         datasource.Policies(),
-				"tg_cluster_connectors": datasource.ClusterConnectors(),
-				"tg_cluster_services":   datasource.ClusterServices(),
-				"tg_node_connectors":    datasource.NodeConnectors(),
-				"tg_node_services":      datasource.NodeServices(),
-				"tg_service_user":       datasource.ServiceUser(),
-				"tg_service_users":      datasource.ServiceUsers(),
-				"tg_shadow":           datasource.Shadow(),
-				"tg_user":             datasource.User(),
-				"tg_users":            datasource.Users(),
-				"tg_virtual_network":  datasource.VirtualNetwork(),
+				"tg_alarm":                   datasource.Alarm(),
+				"tg_alarms":                  datasource.Alarms(),
+				"tg_alarm_channel":           datasource.AlarmChannel(),
+				"tg_alarm_channels":          datasource.AlarmChannels(),
+				"tg_app":                     datasource.App(),
+				"tg_cert":                    datasource.Cert(),
+				"tg_cluster":                 datasource.Cluster(),
+				"tg_clusters":                datasource.Clusters(),
+				"tg_device_info":             datasource.Device(),
+				"tg_group":                   datasource.Group(),
+				"tg_idp":                     datasource.IDP(),
+				"tg_network_config":          datasource.NetworkConfig(),
+				"tg_node":                    datasource.Node(),
+				"tg_node_iface_names":        datasource.NodeIfaceNames(),
+				"tg_nodes":                   datasource.Nodes(),
+				"tg_org":                     datasource.Org(),
+				"tg_kvm_image":               datasource.KVMImage(),
+				"tg_kvm_volume":              datasource.KVMVolume(),
+				"tg_policy":                  datasource.Policy(),
+				"tg_policies":                datasource.Policies(),
+				"tg_cluster_connectors":      datasource.ClusterConnectors(),
+				"tg_cluster_services":        datasource.ClusterServices(),
+				"tg_node_connectors":         datasource.NodeConnectors(),
+				"tg_node_services":           datasource.NodeServices(),
+				"tg_service_user":            datasource.ServiceUser(),
+				"tg_service_users":           datasource.ServiceUsers(),
+				"tg_shadow":                  datasource.Shadow(),
+				"tg_user":                    datasource.User(),
+				"tg_users":                   datasource.Users(),
+				"tg_virtual_network":         datasource.VirtualNetwork(),
+				"tg_virtual_network_objects": datasource.VNetObjects(),
+				"tg_virtual_network_groups":  datasource.VNetGroups(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"tg_alarm":                            resource.Alarm(),
@@ -130,6 +247,7 @@ func New(version string) func() *schema.Provider {
 				"tg_virtual_network":                  resource.VirtualNetwork(),
 				"tg_virtual_network_access_rule":      resource.VNetAccessRule(),
 				"tg_virtual_network_attachment":       resource.VNetAttachment(),
+				"tg_virtual_network_commit":           resource.VNetCommit(),
 				"tg_virtual_network_group":            resource.VNetGroup(),
 				"tg_virtual_network_group_membership": resource.VNetGroupMembership(),
 				"tg_virtual_network_object":           resource.VNetObject(),
@@ -150,17 +268,73 @@ func New(version string) func() *schema.Provider {
 	}
 }
 
+// Server returns the gRPC provider server for the provider, which adds support for `moved` blocks
+// between resource types to the plugin SDK's.
+func Server(version string) func() tfprotov5.ProviderServer {
+	return func() tfprotov5.ProviderServer {
+		return majordomo.ProviderServer(New(version)(), map[string][]majordomo.StateMover{
+			"tg_node_service": resource.NodeServiceMovers(),
+		})
+	}
+}
+
 func configure(_ string, _ *schema.Provider) func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
+		p, err := loadProfile(d.Get("shared_credentials_file").(string), d.Get("profile").(string)) //nolint: errcheck // just trusting TF validation here
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		// Provider arguments and environment variables win over the profile.
+		setting := func(key string) string {
+			if v, ok := d.Get(key).(string); ok && v != "" {
+				return v
+			}
+			return p[key]
+		}
+
 		cp := tg.ClientParams{
-			APIKey:    d.Get("api_key_id").(string),     //nolint: errcheck // just trusting TF validation here
-			APISecret: d.Get("api_key_secret").(string), //nolint: errcheck // just trusting TF validation here
-			APIHost:   d.Get("api_host").(string),       //nolint: errcheck // just trusting TF validation here
-			JWT:       d.Get("api_jwt").(string),        //nolint: errcheck // just trusting TF validation here
+			APIKey:       setting("api_key_id"),
+			APISecret:    setting("api_key_secret"),
+			APIHost:      setting("api_host"),
+			JWT:          setting("api_jwt"),
+			OrgID:        setting("org_id"),
+			ClientID:     setting("client_id"),
+			ClientSecret: setting("client_secret"),
+			TokenPath:    setting("token_path"),
+			BaseURL:      setting("api_base_url"),
+		}
+		if cp.APIHost == "" {
+			cp.APIHost = defaultAPIHost
+		}
+		if retries, ok := d.Get("max_retries").(int); ok {
+			cp.MaxRetries = retries
+		}
+		if wait, ok := d.Get("retry_max_wait").(int); ok {
+			cp.RetryMaxWait = time.Duration(wait) * time.Second
+		}
+
+		if rps, ok := d.Get("requests_per_second").(float64); ok {
+			cp.RequestsPerSecond = rps
+		}
+		if burst, ok := d.Get("burst").(int); ok {
+			cp.Burst = burst
 		}
-		if orgid, ok := d.Get("org_id").(string); ok {
-			cp.OrgID = orgid
+
+		if deferCommits, ok := d.Get("defer_network_commits").(bool); ok {
+			cp.DeferNetworkCommits = deferCommits
+		}
+
+		if !cacheDisabled() {
+			cp.CacheTTL = tg.DefaultCacheTTL
+		}
+
+		httpClient, err := newHTTPClient(d)
+		if err != nil {
+			return nil, diag.FromErr(err)
 		}
+		cp.HTTPClient = httpClient
+
 		c, err := tg.NewClient(ctx, cp)
 
 		if err != nil {
@@ -169,3 +343,46 @@ func configure(_ string, _ *schema.Provider) func(context.Context, *schema.Resou
 		return c, nil
 	}
 }
+
+// cacheDisabled reports whether TG_DISABLE_CACHE asks for every read to go to the portal.
+func cacheDisabled() bool {
+	v := strings.ToLower(os.Getenv("TG_DISABLE_CACHE"))
+	return v != "" && v != "0" && v != "false"
+}
+
+// newHTTPClient builds the client used to reach the portal from the proxy and TLS settings.
+func newHTTPClient(d *schema.ResourceData) (*http.Client, error) {
+	cfg := tg.TransportConfig{
+		ProxyURL:           d.Get("proxy_url").(string),          //nolint: errcheck // just trusting TF validation here
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool), //nolint: errcheck // just trusting TF validation here
+	}
+
+	if path, ok := d.Get("ca_cert_file").(string); ok && path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_cert_file: %w", err)
+		}
+		cfg.CACertPEM = pem
+	}
+
+	var err error
+	if cfg.ClientCertPEM, err = pemOrFile(d.Get("client_cert").(string)); err != nil { //nolint: errcheck // just trusting TF validation here
+		return nil, fmt.Errorf("error reading client_cert: %w", err)
+	}
+	if cfg.ClientKeyPEM, err = pemOrFile(d.Get("client_key").(string)); err != nil { //nolint: errcheck // just trusting TF validation here
+		return nil, fmt.Errorf("error reading client_key: %w", err)
+	}
+
+	return tg.NewHTTPClient(cfg)
+}
+
+// pemOrFile returns the value itself if it's PEM-encoded, otherwise the contents of the file it names.
+func pemOrFile(v string) ([]byte, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(v), "-----BEGIN") {
+		return []byte(v), nil
+	}
+	return os.ReadFile(v)
+}
@@ -34,6 +34,7 @@ func Alarm() *schema.Resource {
 			ID: func(a hcl.Alarm) string {
 				return a.UID
 			},
+			ImportID: []string{"{uid}"},
 		})
 
 	return &schema.Resource{
@@ -43,6 +44,7 @@ func Alarm() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -33,6 +33,7 @@ func AlarmChannel() *schema.Resource {
 			ID: func(a hcl.AlarmChannel) string {
 				return a.UID
 			},
+			ImportID: []string{"{uid}"},
 		})
 	return &schema.Resource{
 		Description: "Manage an alarm channel.",
@@ -41,6 +42,7 @@ func AlarmChannel() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -3,12 +3,12 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 	"github.com/trustgrid/terraform-provider-tg/validators"
 )
@@ -26,6 +26,7 @@ func App() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("uid"), "{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"type": {
@@ -211,9 +212,8 @@ func (r *app) Read(ctx context.Context, d *schema.ResourceData, meta any) diag.D
 
 	tgapp := tg.App{}
 	err = tgc.Get(ctx, tf.ResourceURL(d.Id()), &tgapp)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
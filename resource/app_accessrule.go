@@ -3,12 +3,12 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -74,6 +74,7 @@ func AppAccessRule() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("uid"), "{app}/{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"app": {
@@ -82,6 +83,11 @@ func AppAccessRule() *schema.Resource {
 				Required:    true,
 				ForceNew:    true,
 			},
+			"uid": {
+				Description: "Rule ID",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"name": {
 				Description: "App Name",
 				Type:        schema.TypeString,
@@ -146,6 +152,9 @@ func (r *appAccessRule) Create(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set("uid", response.ID); err != nil {
+		return diag.FromErr(err)
+	}
 	d.SetId(response.ID)
 
 	return nil
@@ -192,9 +201,8 @@ func (r *appAccessRule) Read(ctx context.Context, d *schema.ResourceData, meta a
 
 	tgrule := tg.AppAccessRule{}
 	err = tgc.Get(ctx, tf.ResourceURL(d.Id()), &tgrule)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -202,6 +210,7 @@ func (r *appAccessRule) Read(ctx context.Context, d *schema.ResourceData, meta a
 	}
 
 	tf.UpdateFromTG(tgrule)
+	tf.UID = d.Id()
 
 	if err := hcl.EncodeResourceData(tf, d); err != nil {
 		return diag.FromErr(err)
@@ -3,12 +3,12 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -25,6 +25,7 @@ func AppACL() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("uid"), "{app}/{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"app": {
@@ -33,6 +34,11 @@ func AppACL() *schema.Resource {
 				Required:    true,
 				ForceNew:    true,
 			},
+			"uid": {
+				Description: "ACL ID",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"protocol": {
 				Description:  "Protocol",
 				Type:         schema.TypeString,
@@ -80,6 +86,9 @@ func (r *appACL) Create(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set("uid", response.ID); err != nil {
+		return diag.FromErr(err)
+	}
 	d.SetId(response.ID)
 
 	return nil
@@ -126,9 +135,8 @@ func (r *appACL) Read(ctx context.Context, d *schema.ResourceData, meta any) dia
 
 	tgacl := tg.AppACL{}
 	err = tgc.Get(ctx, tf.ResourceURL(d.Id()), &tgacl)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -136,6 +144,7 @@ func (r *appACL) Read(ctx context.Context, d *schema.ResourceData, meta any) dia
 	}
 
 	tf.UpdateFromTG(tgacl)
+	tf.UID = d.Id()
 
 	if err := hcl.EncodeResourceData(tf, d); err != nil {
 		return diag.FromErr(err)
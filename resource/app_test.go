@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+const testImportIDPID = "0f9e8d7c-6b5a-4c3d-2e1f-0a9b8c7d6e5f"
+
+func identityImportClient(t *testing.T) *tg.Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/application/app1/access-rule/rule1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"name":"staff","action":"allow","includes":{"emailsEndingIn":["@example.com"]}}`)
+	})
+	mux.HandleFunc("GET /api/v2/idp/openid/"+testImportIDPID, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"issuer":"https://login.example.com","clientId":"tg","secret":"","authEndpoint":"https://login.example.com/auth"}`)
+	})
+	mux.HandleFunc("GET /api/v2/group/g1/members", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"user":"alice@example.com"}]`)
+	})
+	return newTestClient(t, mux, tg.ClientParams{})
+}
+
+func TestAppAccessRuleImport(t *testing.T) {
+	tgc := identityImportClient(t)
+
+	d, err := importState(t, AppAccessRule(), tgc, "app1/rule1")
+	require.NoError(t, err)
+	assert.Equal(t, "rule1", d.Id())
+	assert.Equal(t, "rule1", d.Get("uid"))
+	assert.Equal(t, "app1", d.Get("app"))
+	assert.Equal(t, "staff", d.Get("name"))
+
+	_, err = importState(t, AppAccessRule(), tgc, "app1/rule2")
+	assert.ErrorContains(t, err, `nothing found for import ID "app1/rule2"`)
+}
+
+func TestIDPOpenIDConfigImport_KeepsSecret(t *testing.T) {
+	tgc := identityImportClient(t)
+
+	d, err := importState(t, IDPOpenIDConfig(), tgc, testImportIDPID)
+	require.NoError(t, err)
+	assert.Equal(t, testImportIDPID, d.Id())
+	assert.Equal(t, "tg", d.Get("client_id"))
+	assert.Empty(t, d.Get("secret"))
+
+	// Once the secret is in state, refreshes must not blank it just because the API doesn't return it.
+	require.NoError(t, d.Set("secret", "s3cret"))
+	diags := IDPOpenIDConfig().ReadContext(t.Context(), d, tgc)
+	require.False(t, diags.HasError())
+	assert.Equal(t, "s3cret", d.Get("secret"))
+}
+
+func TestGroupMembershipImport(t *testing.T) {
+	tgc := identityImportClient(t)
+
+	d, err := importState(t, GroupMembership(), tgc, "g1/alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "g1-alice@example.com", d.Id())
+
+	_, err = importState(t, GroupMember(), tgc, "g1/bob@example.com")
+	assert.ErrorContains(t, err, `nothing found for import ID "g1/bob@example.com"`)
+}
@@ -20,6 +20,7 @@ func Cert() *schema.Resource {
 			RemoteID: func(cert tg.Cert) string {
 				return cert.FQDN
 			},
+			ImportID: []string{"{fqdn}"},
 		})
 
 	return &schema.Resource{
@@ -29,6 +30,7 @@ func Cert() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"fqdn": {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -25,6 +26,7 @@ func Cluster() *schema.Resource {
 		ReadContext:   c.Read,
 		DeleteContext: c.Delete,
 		CreateContext: c.Create,
+		Importer:      majordomo.Importer(c.Read, nil, "{fqdn}"),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -81,15 +83,23 @@ func (cr *cluster) Read(ctx context.Context, d *schema.ResourceData, meta any) d
 	var cluster tg.Cluster
 
 	err := tgc.Get(ctx, "/cluster/"+d.Id(), &cluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
 		return diag.FromErr(err)
 	}
 
+	if cluster.Name != "" {
+		if err := d.Set("name", cluster.Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("fqdn", d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -2,8 +2,8 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,7 +13,9 @@ import (
 	"github.com/trustgrid/terraform-provider-tg/validators"
 )
 
-type clusterActiveMember struct{}
+type clusterActiveMember struct {
+	poller tg.Poller
+}
 
 // ClusterActiveMember designates the active master of a cluster.
 //
@@ -53,6 +55,11 @@ func ClusterActiveMember() *schema.Resource {
 		DeleteContext: c.Delete,
 		CreateContext: c.Create,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"cluster_fqdn": {
 				Description:  "Cluster FQDN (typically `tg_cluster.<name>.fqdn`).",
@@ -67,6 +74,13 @@ func ClusterActiveMember() *schema.Resource {
 				Required:     true,
 				ValidateFunc: validation.IsUUID,
 			},
+			"wait_for_active": {
+				Description: "Wait until the node reports itself as the active node after promoting it, up to the create or update timeout. " +
+					"The node has to be online for this to complete.",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
@@ -81,6 +95,23 @@ func (c *clusterActiveMember) promote(ctx context.Context, tgc *tg.Client, fqdn,
 	return nil
 }
 
+// waitForActive polls the node until its reported state shows it took over as the active
+// master, which happens asynchronously after the promote call returns. The node document is
+// cached, so every check reads it from the portal.
+func (c *clusterActiveMember) waitForActive(ctx context.Context, tgc *tg.Client, fqdn, nodeID string) error {
+	err := c.poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		var n tg.Node
+		if err := tgc.Get(tg.WithoutCache(ctx), "/node/"+nodeID, &n); err != nil {
+			return false, err
+		}
+		return n.Shadow.Reported["cluster.master"] == "true", nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for %s to become active master of %s: %w", nodeID, fqdn, err)
+	}
+	return nil
+}
+
 func (c *clusterActiveMember) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 	tf, err := hcl.DecodeResourceData[hcl.ClusterActiveMember](d)
@@ -91,6 +122,11 @@ func (c *clusterActiveMember) Create(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 	d.SetId(tf.ClusterFQDN)
+	if tf.WaitForActive {
+		if err := c.waitForActive(ctx, tgc, tf.ClusterFQDN, tf.NodeID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	return nil
 }
 
@@ -106,6 +142,11 @@ func (c *clusterActiveMember) Update(ctx context.Context, d *schema.ResourceData
 	if err := c.promote(ctx, tgc, d.Id(), tf.NodeID); err != nil {
 		return diag.FromErr(err)
 	}
+	if tf.WaitForActive {
+		if err := c.waitForActive(ctx, tgc, d.Id(), tf.NodeID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	return nil
 }
 
@@ -125,9 +166,8 @@ func (c *clusterActiveMember) Read(ctx context.Context, d *schema.ResourceData,
 
 	var cluster tg.Cluster
 	err := tgc.Get(ctx, "/cluster/"+d.Id(), &cluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
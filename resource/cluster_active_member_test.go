@@ -0,0 +1,39 @@
+package resource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func TestClusterActiveMember_WaitForActiveSkipsCache(t *testing.T) {
+	// The node takes over a couple of reads after the promotion, like it would a few seconds later.
+	var reads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/n1", func(w http.ResponseWriter, _ *http.Request) {
+		if reads.Add(1) > 2 {
+			_, _ = io.WriteString(w, `{"uid":"n1","shadow":{"reported":{"cluster.master":"true"}}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"uid":"n1","shadow":{"reported":{"cluster.master":"false"}}}`)
+	})
+	mux.HandleFunc("PUT /api/cluster/c1.example.trustgrid.io/active/n1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{}`)
+	})
+	tgc := newTestClient(t, mux, tg.ClientParams{CacheTTL: tg.DefaultCacheTTL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	c := clusterActiveMember{poller: tg.Poller{Interval: time.Millisecond}}
+	require.NoError(t, c.promote(ctx, tgc, "c1.example.trustgrid.io", "n1"))
+	require.NoError(t, c.waitForActive(ctx, tgc, "c1.example.trustgrid.io", "n1"))
+	assert.EqualValues(t, 3, reads.Load())
+}
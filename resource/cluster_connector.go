@@ -3,9 +3,7 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,21 +14,6 @@ import (
 	"github.com/trustgrid/terraform-provider-tg/validators"
 )
 
-func clusterConnectorImport(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
-	parts := strings.SplitN(d.Id(), ":", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return nil, fmt.Errorf("expected import ID in form {cluster_fqdn}:{connector_id}, got %q", d.Id())
-	}
-	if err := d.Set("cluster_fqdn", parts[0]); err != nil {
-		return nil, err
-	}
-	if err := d.Set("connector_id", parts[1]); err != nil {
-		return nil, err
-	}
-	d.SetId(parts[1])
-	return []*schema.ResourceData{d}, nil
-}
-
 func clusterConnectorRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
@@ -42,9 +25,8 @@ func clusterConnectorRead(ctx context.Context, d *schema.ResourceData, meta any)
 
 	var cluster tg.Cluster
 	err := tgc.Get(ctx, fmt.Sprintf("/cluster/%s", fqdn), &cluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -114,9 +96,7 @@ func ClusterConnector() *schema.Resource {
 		ReadContext:   clusterConnectorRead,
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
-		Importer: &schema.ResourceImporter{
-			StateContext: clusterConnectorImport,
-		},
+		Importer:      majordomo.Importer(clusterConnectorRead, majordomo.AttributeID("connector_id"), "{cluster_fqdn}:{connector_id}"),
 		Schema: map[string]*schema.Schema{
 			"connector_id": {
 				Description: "Connector unique ID. Computed after create.",
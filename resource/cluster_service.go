@@ -3,9 +3,7 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -28,22 +26,6 @@ func clusterServiceValidate(_ context.Context, d *schema.ResourceDiff, _ any) er
 	return nil
 }
 
-// clusterServiceImport parses an import ID of the form "{cluster_fqdn}:{service_id}".
-func clusterServiceImport(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
-	parts := strings.SplitN(d.Id(), ":", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return nil, fmt.Errorf("expected import ID in form {cluster_fqdn}:{service_id}, got %q", d.Id())
-	}
-	if err := d.Set("cluster_fqdn", parts[0]); err != nil {
-		return nil, err
-	}
-	if err := d.Set("service_id", parts[1]); err != nil {
-		return nil, err
-	}
-	d.SetId(parts[1])
-	return []*schema.ResourceData{d}, nil
-}
-
 // clusterServiceRead pulls the cluster, looks up the service by ID. Relies on
 // tg.ServicesConfig.UnmarshalJSON to handle both V1 and V2 cluster shapes.
 func clusterServiceRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -57,9 +39,8 @@ func clusterServiceRead(ctx context.Context, d *schema.ResourceData, meta any) d
 
 	var cluster tg.Cluster
 	err := tgc.Get(ctx, fmt.Sprintf("/cluster/%s", fqdn), &cluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -130,9 +111,7 @@ func ClusterService() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CustomizeDiff: clusterServiceValidate,
-		Importer: &schema.ResourceImporter{
-			StateContext: clusterServiceImport,
-		},
+		Importer:      majordomo.Importer(clusterServiceRead, majordomo.AttributeID("service_id"), "{cluster_fqdn}:{service_id}"),
 		Schema: map[string]*schema.Schema{
 			"service_id": {
 				Description: "Service unique ID. Computed after create.",
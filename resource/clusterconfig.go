@@ -2,7 +2,6 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -24,6 +23,7 @@ func ClusterConfig() *schema.Resource {
 		ReadContext:   c.Read,
 		UpdateContext: c.Update,
 		DeleteContext: c.Delete,
+		Importer:      nodeOrClusterImporter(c.Read, nil, true),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -108,9 +108,8 @@ func (cr *clusterconfig) Read(ctx context.Context, d *schema.ResourceData, meta
 	tgc := tg.GetClient(meta)
 
 	cc, err := cr.getConfig(ctx, tgc, d.Id())
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -146,9 +145,8 @@ func (cr *clusterconfig) Update(ctx context.Context, d *schema.ResourceData, met
 func (cr *clusterconfig) Delete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 	cc, err := cr.getConfig(ctx, tgc, d.Id())
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		return nil
 	case err != nil:
 		return diag.FromErr(err)
@@ -84,9 +84,8 @@ func (nr *clustermember) Read(ctx context.Context, d *schema.ResourceData, meta
 
 	n := tg.Node{}
 	err := tgc.Get(ctx, "/node/"+d.Id(), &n)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -124,8 +124,8 @@ func (r *connector) Create(ctx context.Context, d *schema.ResourceData, meta any
 
 	payload := tf.ToTG(uuid.NewString())
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(nodeOrClusterTarget(tf.NodeID, tf.ClusterFQDN))
+	defer unlock()
 
 	config, err := r.getConfig(ctx, tgc, tf)
 	if err != nil {
@@ -153,8 +153,8 @@ func (r *connector) Update(ctx context.Context, d *schema.ResourceData, meta any
 
 	payload := tf.ToTG(d.Id())
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(nodeOrClusterTarget(tf.NodeID, tf.ClusterFQDN))
+	defer unlock()
 
 	config, err := r.getConfig(ctx, tgc, tf)
 	if err != nil {
@@ -184,8 +184,8 @@ func (r *connector) Delete(ctx context.Context, d *schema.ResourceData, meta any
 		return diag.FromErr(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(nodeOrClusterTarget(tf.NodeID, tf.ClusterFQDN))
+	defer unlock()
 
 	config, err := r.getConfig(ctx, tgc, tf)
 	if err != nil {
@@ -2,21 +2,23 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
-	"github.com/trustgrid/terraform-provider-tg/validators"
 	"golang.org/x/sync/errgroup"
 )
 
 type container struct {
 }
 
+// containerDiagnostics points errors at the container attributes they are about.
+var containerDiagnostics = hcl.Diagnostics[hcl.Container, tg.Container]
+
 // Container manages a node or cluster container.
 func Container() *schema.Resource {
 	c := container{}
@@ -28,112 +30,9 @@ func Container() *schema.Resource {
 		UpdateContext: c.Update,
 		DeleteContext: c.Delete,
 		CreateContext: c.Create,
+		Importer:      majordomo.Importer(c.Read, majordomo.AttributeID("id"), "node:{node_id}/{id}", "cluster:{cluster_fqdn}/{id}"),
 
-		Schema: map[string]*schema.Schema{
-			"node_id": {
-				Description:  "Node ID",
-				Type:         schema.TypeString,
-				Optional:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.IsUUID,
-				ExactlyOneOf: []string{"node_id", "cluster_fqdn"},
-			},
-			"cluster_fqdn": {
-				Description:  "Cluster FQDN",
-				Type:         schema.TypeString,
-				Optional:     true,
-				ForceNew:     true,
-				ValidateFunc: validators.IsHostname,
-				ExactlyOneOf: []string{"node_id", "cluster_fqdn"},
-			},
-			"id": {
-				Description: "Container ID",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"name": {
-				Description: "Container Name",
-				Type:        schema.TypeString,
-				Required:    true,
-			},
-			"command": {
-				Description: "Command",
-				Type:        schema.TypeString,
-				Optional:    true,
-			},
-			"description": {
-				Description: "Description",
-				Type:        schema.TypeString,
-				Optional:    true,
-			},
-			"enabled": {
-				Description: "Enabled",
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     true,
-			},
-			"exec_type": {
-				Description:  `Container execution type - one of "onDemand", "service", or "recurring"`,
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice([]string{"onDemand", "service", "recurring"}, false),
-			},
-			"hostname": {
-				Description: "Host name",
-				Type:        schema.TypeString,
-				Optional:    true,
-			},
-			"image": {
-				Type:     schema.TypeList,
-				Required: true,
-				MaxItems: 1,
-				MinItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"repository": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Image repository",
-						},
-						"tag": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Image tag",
-						},
-					},
-				},
-			},
-			"privileged": {
-				Description: "Grant extended privileges to the container",
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-			},
-			"require_connectivity": {
-				Description: "Ensures that a container that has encrypted volumes won't start unless the node has connectivity to the control plane",
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-			},
-			"stop_time": {
-				Description: "Time to wait, in seconds, for container to stop gracefully",
-				Default:     30,
-				Type:        schema.TypeInt,
-				Optional:    true,
-			},
-			"use_init": {
-				Description: "Indicates that an init process should be used as PID 1 in the container. Ensures responsibilities of an init system are performed inside the container (i.e., handling exit signals)",
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-			},
-			"user": {
-				Description: "User",
-				Type:        schema.TypeString,
-				Optional:    true,
-			},
-
-			// extended config
+		Schema: hcl.Schema[hcl.Container](map[string]*schema.Schema{
 			"add_caps": {
 				Description: "Add Linux capabilities from the container to have fine grain control over kernel features and device access",
 				Type:        schema.TypeList,
@@ -193,268 +92,24 @@ func Container() *schema.Resource {
 					}, false),
 				},
 			},
-			"variables": {
-				Description: "Environment variables",
-				Type:        schema.TypeMap,
-				Optional:    true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
+			"mount.type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Mount type - `volume` or `bind`",
+				ValidateFunc: validation.StringInSlice([]string{"volume", "bind"}, false),
 			},
-			"vrf": {
-				Description: "Container VRF",
+			"mount.source": {
 				Type:        schema.TypeString,
-				Optional:    true,
-			},
-			"log_max_file_size": {
-				Description: "Maximum log file size (MB)",
-				Type:        schema.TypeInt,
-				Optional:    true,
-			},
-			"log_max_num_files": {
-				Description: "Maximum log files to keep",
-				Type:        schema.TypeInt,
-				Optional:    true,
-			},
-			"healthcheck": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				MinItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"command": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Command",
-						},
-						"interval": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Interval",
-						},
-						"timeout": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Timeout",
-						},
-						"start_period": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Grace period before health checks are monitored, in seconds",
-						},
-						"retries": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Number of health checks that must fail before a container is considered unhealthy",
-						},
-					},
-				},
-			},
-			"mount": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"uid": {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: "Mount ID (for API use only)",
-						},
-						"type": {
-							Type:         schema.TypeString,
-							Required:     true,
-							Description:  "Mount type - `volume` or `bind`",
-							ValidateFunc: validation.StringInSlice([]string{"volume", "bind"}, false),
-						},
-						"source": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "For `volume` mounts, the name of the volume. For `bind` mounts, the path to the file or directory on the host datastore",
-						},
-						"dest": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Destination path in the container filesystem",
-						},
-					},
-				},
-			},
-			"limits": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				MinItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"cpu_max": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "CPU max allocation %",
-						},
-						"io_rbps": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Max allowed read throughput (bytes per second)",
-						},
-						"io_wbps": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Max allowed write throughput (bytes per second)",
-						},
-						"io_riops": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Max allowed read throughput (IOPS)",
-						},
-						"io_wiops": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Description: "Max allowed write throughput (IOPS)",
-						},
-						"mem_high": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Soft RAM allocation limit (MB)",
-						},
-						"mem_max": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Hard RAM allocation limit (MB)",
-						},
-						"limits": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Description: "Linux kernel limits",
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"type": {
-										Type:        schema.TypeString,
-										Required:    true,
-										Description: "Limit type",
-										ValidateFunc: validation.StringInSlice([]string{
-											"core",
-											"cpu",
-											"data",
-											"fsize",
-											"locks",
-											"memlock",
-											"msgqueue",
-											"nice",
-											"nofile",
-											"nproc",
-											"rss",
-											"rtprio",
-											"rttime",
-											"sigpending",
-											"stack",
-										}, false),
-									},
-									"hard": {
-										Type:        schema.TypeInt,
-										Required:    true,
-										Description: "Hard limit",
-									},
-									"soft": {
-										Type:        schema.TypeInt,
-										Required:    true,
-										Description: "Soft limit",
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-			"port_mapping": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"uid": {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: "Port Mapping ID (for API use only)",
-						},
-						"protocol": {
-							Type:         schema.TypeString,
-							Required:     true,
-							Description:  "Protocol - `tcp` or `udp`",
-							ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
-						},
-						"iface": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Host interface to expose port",
-						},
-						"host_port": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Host port",
-						},
-						"container_port": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Container port",
-						},
-					},
-				},
-			},
-			"virtual_network": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"uid": {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: "VNet ID (for API use only)",
-						},
-						"network": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Virtual network name to attach - use the tg_virtual_network resource's exported name to help Terraform build a consistent dependency graph",
-						},
-						"ip": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Virtual IP address",
-						},
-						"allow_outbound": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-							Description: "Allow outbound connections on this network",
-						},
-					},
-				},
+				Required:    true,
+				Description: "For `volume` mounts, the name of the volume. For `bind` mounts, the path to the file or directory on the host datastore",
 			},
-			"interface": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"uid": {
-							Type:        schema.TypeString,
-							Computed:    true,
-							Description: "Interface ID (for API use only)",
-						},
-						"name": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Virtual interface name",
-						},
-						"dest": {
-							Type:         schema.TypeString,
-							Required:     true,
-							Description:  "Internal interface destination IP",
-							ValidateFunc: validation.IsIPv4Address,
-						},
-					},
-				},
+			"port_mapping.protocol": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Protocol - `tcp` or `udp`",
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
 			},
-		},
+		}),
 	}
 }
 
@@ -525,9 +180,21 @@ func (cr *container) getContainer(ctx context.Context, tgc *tg.Client, c hcl.Con
 	return res, err
 }
 
-func (cr *container) writeExtendedConfig(ctx context.Context, tgc *tg.Client, c hcl.Container) error {
+// writeExtendedConfig writes everything but the container itself. Its errors are diagnostics
+// since the portal validates a different body than the container's.
+func (cr *container) writeExtendedConfig(ctx context.Context, tgc *tg.Client, c hcl.Container) diag.Diagnostics {
 	_, err := tgc.Put(ctx, cr.containerURL(c)+"/config", c.ToTG().Config)
-	return err
+	return hcl.Diagnostics[hcl.Container, tg.ContainerConfig](err)
+}
+
+// containerWarnings flags settings that are accepted but have no effect.
+func containerWarnings(c hcl.Container) diag.Diagnostics {
+	if c.Privileged && len(c.AddCaps) > 0 {
+		return diag.Diagnostics{hcl.AttributeWarning(hcl.AttributePath("add_caps"),
+			"add_caps has no effect on privileged containers",
+			"Privileged containers already have every Linux capability, so the ones in add_caps are redundant.")}
+	}
+	return nil
 }
 
 func (cr *container) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -535,26 +202,26 @@ func (cr *container) Create(ctx context.Context, d *schema.ResourceData, meta an
 
 	ct, err := hcl.DecodeResourceData[hcl.Container](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 	ct.SetUIDs()
 
 	ct.ID = uuid.New().String()
 
 	if _, err := tgc.Post(ctx, cr.urlRoot(ct), ct.ToTG()); err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
 	d.SetId(ct.ID)
 
-	if err := cr.writeExtendedConfig(ctx, tgc, ct); err != nil {
-		return diag.FromErr(err)
+	if diags := cr.writeExtendedConfig(ctx, tgc, ct); diags.HasError() {
+		return diags
 	}
 
 	if err := hcl.EncodeResourceData(ct, d); err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
-	return nil
+	return containerWarnings(ct)
 }
 
 func (cr *container) Update(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -562,23 +229,23 @@ func (cr *container) Update(ctx context.Context, d *schema.ResourceData, meta an
 
 	ct, err := hcl.DecodeResourceData[hcl.Container](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 	ct.SetUIDs()
 
 	if _, err := tgc.Put(ctx, cr.containerURL(ct), ct.ToTG()); err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
-	if err := cr.writeExtendedConfig(ctx, tgc, ct); err != nil {
-		return diag.FromErr(err)
+	if diags := cr.writeExtendedConfig(ctx, tgc, ct); diags.HasError() {
+		return diags
 	}
 
 	if err := hcl.EncodeResourceData(ct, d); err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
-	return nil
+	return containerWarnings(ct)
 }
 
 func (cr *container) Delete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -586,11 +253,11 @@ func (cr *container) Delete(ctx context.Context, d *schema.ResourceData, meta an
 
 	ct, err := hcl.DecodeResourceData[hcl.Container](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
 	if err := tgc.Delete(ctx, cr.containerURL(ct), &ct); err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
 	return nil
@@ -601,23 +268,22 @@ func (cr *container) Read(ctx context.Context, d *schema.ResourceData, meta any)
 
 	tf, err := hcl.DecodeResourceData[hcl.Container](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
 	ct, err := cr.getContainer(ctx, tgc, tf)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
 	tf.UpdateFromTG(ct)
 
 	if err := hcl.EncodeResourceData(tf, d); err != nil {
-		return diag.FromErr(err)
+		return containerDiagnostics(err)
 	}
 
 	return nil
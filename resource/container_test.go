@@ -0,0 +1,109 @@
+package resource
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func containerImportClient(t *testing.T) *tg.Client {
+	t.Helper()
+
+	responses := map[string]string{
+		"":                 `{"id":"c1","name":"web","enabled":true,"execType":"service","stopTime":30,"image":{"repository":"nginx","tag":"1.27"}}`,
+		"/healthcheck":     `{"command":"curl localhost","interval":10,"timeout":5,"startPeriod":30,"retries":3}`,
+		"/limit":           `{"cpuMax":50,"memMax":512,"limits":[{"type":"nofile","soft":512,"hard":1024}]}`,
+		"/capability":      `{"addCaps":["NET_ADMIN"],"dropCaps":[]}`,
+		"/variable":        `[{"name":"MODE","value":"prod"}]`,
+		"/logging":         `{"maxLogFileSize":10,"numFiles":2}`,
+		"/mount":           `[{"uid":"m-z","mountType":"volume","source":"data","dest":"/data"},{"uid":"m-a","mountType":"bind","source":"/etc/ssl","dest":"/ssl"}]`,
+		"/port-mapping":    `[{"uid":"p-1","protocol":"tcp","iface":"ens160","hostPort":8080,"containerPort":80}]`,
+		"/virtual-network": `[{"uid":"v-1","network":"vnet1","ip":"10.0.0.5","allowOutbound":true}]`,
+		"/interface":       `[]`,
+	}
+
+	mux := http.NewServeMux()
+	for suffix, body := range responses {
+		mux.HandleFunc("GET /api/v2/cluster/edge.example.com/exec/container/c1"+suffix, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, body)
+		})
+	}
+	return newTestClient(t, mux, tg.ClientParams{})
+}
+
+func TestContainerImport(t *testing.T) {
+	tgc := containerImportClient(t)
+
+	d, err := importState(t, Container(), tgc, "cluster:edge.example.com/c1")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", d.Id())
+	assert.Equal(t, "edge.example.com", d.Get("cluster_fqdn"))
+	assert.Empty(t, d.Get("node_id"))
+	assert.Equal(t, "web", d.Get("name"))
+
+	imported, err := hcl.DecodeResourceData[hcl.Container](d)
+	require.NoError(t, err)
+	require.Len(t, imported.Mounts, 2)
+	assert.Equal(t, "m-z", imported.Mounts[0].UID)
+	assert.Equal(t, map[string]string{"MODE": "prod"}, imported.Variables)
+
+	// Refreshing the imported state must not change it, or the first plan would show a diff.
+	ct, err := (&container{}).getContainer(t.Context(), tgc, imported)
+	require.NoError(t, err)
+	imported.UpdateFromTG(ct)
+	refreshed := Container().TestResourceData()
+	refreshed.SetId(d.Id())
+	require.NoError(t, hcl.EncodeResourceData(imported, refreshed))
+	assert.Equal(t, d.State().Attributes, refreshed.State().Attributes)
+
+	_, err = importState(t, Container(), tgc, "cluster:edge.example.com/c2")
+	assert.ErrorContains(t, err, `nothing found for import ID "cluster:edge.example.com/c2"`)
+
+	_, err = importState(t, Container(), tgc, "c1")
+	assert.EqualError(t, err, "unexpected import ID \"c1\", expected `node:<node_id>/<id>` or `cluster:<cluster_fqdn>/<id>`")
+}
+
+func TestContainerCreate_RejectedConfig(t *testing.T) {
+	const nodeID = "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/node/"+nodeID+"/exec/container", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{}`)
+	})
+	mux.HandleFunc("PUT /api/v2/node/"+nodeID+"/exec/container/{id}/config", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = io.WriteString(w, `{"errors":[{"message":"unknown volume","field":"mounts[0].source"}]}`)
+	})
+	tgc := newTestClient(t, mux, tg.ClientParams{})
+
+	r := Container()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"node_id":   nodeID,
+		"name":      "web",
+		"exec_type": "service",
+		"image":     []any{map[string]any{"repository": "nginx", "tag": "1.27"}},
+		"mount":     []any{map[string]any{"type": "volume", "source": "data", "dest": "/data"}},
+	})
+
+	diags := r.CreateContext(t.Context(), d, tgc)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "unknown volume", diags[0].Summary)
+	assert.Equal(t, hcl.AttributePath("mount.0.source"), diags[0].AttributePath)
+}
+
+func TestContainerWarnings(t *testing.T) {
+	assert.Empty(t, containerWarnings(hcl.Container{AddCaps: []string{"NET_ADMIN"}}))
+	assert.Empty(t, containerWarnings(hcl.Container{Privileged: true}))
+
+	diags := containerWarnings(hcl.Container{Privileged: true, AddCaps: []string{"NET_ADMIN"}})
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, hcl.AttributePath("add_caps"), diags[0].AttributePath)
+}
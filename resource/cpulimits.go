@@ -2,7 +2,6 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -33,6 +32,12 @@ func (limit *cpuLimitData) id() string {
 	return "cpu_limits_" + limit.NodeID
 }
 
+// cpuLimitsImportID derives the resource ID from the imported node ID.
+func cpuLimitsImportID(d *schema.ResourceData) (string, error) {
+	limits := cpuLimitData{NodeID: d.Get("node_id").(string)} //nolint: errcheck // just trusting TF validation here
+	return limits.id(), nil
+}
+
 func CPULimits() *schema.Resource {
 	return &schema.Resource{
 		Description: "Node CPU Limits",
@@ -41,6 +46,7 @@ func CPULimits() *schema.Resource {
 		ReadContext:   cpuLimitsRead,
 		UpdateContext: cpuLimitsUpdate,
 		DeleteContext: cpuLimitsDelete,
+		Importer:      nodeOrClusterImporter(cpuLimitsRead, cpuLimitsImportID, true),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -122,9 +128,8 @@ func cpuLimitsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.D
 	}
 
 	err = tgc.Get(ctx, limits.url(), &limits)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -41,6 +41,7 @@ func GatewayConfig() *schema.Resource {
 		ReadContext:   md.Read,
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
+		Importer:      nodeOrClusterImporter(md.Read, nil, true),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -23,6 +24,7 @@ func Group() *schema.Resource {
 		ReadContext:   r.Read,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, nil, "{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -63,9 +65,8 @@ func (r *group) Read(ctx context.Context, d *schema.ResourceData, meta any) diag
 
 	tgapp := tg.Group{}
 	err := tgc.Get(ctx, tf.ResourceURL(id), &tgapp)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
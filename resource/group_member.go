@@ -2,12 +2,12 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -24,6 +24,7 @@ func GroupMember() *schema.Resource {
 		ReadContext:   r.Read,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, groupMemberID, "{group_id}/{email}"),
 
 		Schema: map[string]*schema.Schema{
 			"group_id": {
@@ -54,9 +55,8 @@ func (r *groupmember) Read(ctx context.Context, d *schema.ResourceData, meta any
 	members := []tg.GroupMember{}
 
 	err = tgc.Get(ctx, "/v2/group/"+tf.GroupID+"/members", &members)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
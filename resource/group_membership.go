@@ -2,12 +2,12 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -24,6 +24,7 @@ func GroupMembership() *schema.Resource {
 		ReadContext:   r.Read,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, groupMemberID, "{group_id}/{email}"),
 
 		Schema: map[string]*schema.Schema{
 			"group_id": {
@@ -42,6 +43,13 @@ func GroupMembership() *schema.Resource {
 	}
 }
 
+// groupMemberID returns the resource ID for a user's membership in a group.
+func groupMemberID(d *schema.ResourceData) (string, error) {
+	groupID := d.Get("group_id").(string) //nolint: errcheck // just trusting TF validation here
+	email := d.Get("email").(string)      //nolint: errcheck // just trusting TF validation here
+	return groupID + "-" + email, nil
+}
+
 // Read checks only to see if the group membership exists, since there are no fields that can be updated.
 func (r *groupmembership) Read(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
@@ -54,9 +62,8 @@ func (r *groupmembership) Read(ctx context.Context, d *schema.ResourceData, meta
 	members := []tg.GroupMember{}
 
 	err = tgc.Get(ctx, "/v2/group/"+tf.GroupID+"/members", &members)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -2,13 +2,13 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -25,6 +25,7 @@ func IDP() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("uid"), "{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"type": {
@@ -121,9 +122,8 @@ func (r *idp) Read(ctx context.Context, d *schema.ResourceData, meta any) diag.D
 
 	tgidp := tg.IDP{}
 	err = tgc.Get(ctx, tf.ResourceURL(), &tgidp)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -2,12 +2,12 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -24,6 +24,7 @@ func IDPSAMLConfig() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("idp_id"), "{idp_id}"),
 
 		Schema: map[string]*schema.Schema{
 			"idp_id": {
@@ -94,9 +95,8 @@ func (r *idpSAMLConfig) Read(ctx context.Context, d *schema.ResourceData, meta a
 
 	tgidp := tg.IDPSAMLConfig{}
 	err = tgc.Get(ctx, tf.ResourceURL(d.Id()), &tgidp)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
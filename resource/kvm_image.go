@@ -2,7 +2,6 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -26,6 +26,7 @@ func KVMImage() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("uid"), "node:{node_id}/{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -127,9 +128,8 @@ func (r *kvmImage) Read(ctx context.Context, d *schema.ResourceData, meta any) d
 
 	tgimg := tg.KVMImage{}
 	err = tgc.Get(ctx, tf.ResourceURL(d.Id()), &tgimg)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
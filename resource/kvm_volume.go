@@ -2,19 +2,45 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type kvmVolume struct {
 }
 
+// kvmVolumeV0 is tg_kvm_volume's model at schema version 0, as released with the Go field for
+// device_bus misspelled DeviseBus.
+type kvmVolumeV0 struct {
+	NodeID        string `tf:"node_id" schema:"required,forcenew"`
+	Name          string `tf:"name" schema:"required,forcenew"`
+	DeviceType    string `tf:"device_type" schema:"required"`
+	DeviseBus     string `tf:"device_bus" schema:"required"`
+	Size          int    `tf:"size" schema:"optional"`
+	ProvisionType string `tf:"provision_type" schema:"optional"`
+	Encrypted     bool   `tf:"encrypted" schema:"optional"`
+	Path          string `tf:"path" schema:"optional"`
+}
+
+func upgradeKVMVolumeV0(_ context.Context, old kvmVolumeV0) (hcl.KVMVolume, error) {
+	return hcl.KVMVolume{
+		NodeID:        old.NodeID,
+		Name:          old.Name,
+		DeviceType:    old.DeviceType,
+		DeviceBus:     old.DeviseBus,
+		Size:          old.Size,
+		ProvisionType: old.ProvisionType,
+		Encrypted:     old.Encrypted,
+		Path:          old.Path,
+	}, nil
+}
+
 func KVMVolume() *schema.Resource {
 	r := kvmVolume{}
 
@@ -25,6 +51,12 @@ func KVMVolume() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("name"), "node:{node_id}/{name}"),
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			majordomo.StateUpgrader(0, hcl.Schema[kvmVolumeV0](nil), upgradeKVMVolumeV0),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -138,9 +170,8 @@ func (r *kvmVolume) Read(ctx context.Context, d *schema.ResourceData, meta any)
 
 	tgimg := tg.KVMVolume{}
 	err = tgc.Get(ctx, tf.ResourceURL(), &tgimg)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
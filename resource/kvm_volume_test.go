@@ -0,0 +1,67 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateFixture reads recorded resource state from testdata/state.
+func stateFixture(t *testing.T, fixture string) []byte {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "state", fixture))
+	require.NoError(t, err)
+	return raw
+}
+
+// decodeState decodes state returned over gRPC for res back into its attributes.
+func decodeState(t *testing.T, res *schema.Resource, state *tfprotov5.DynamicValue) map[string]any {
+	t.Helper()
+	require.NotNil(t, state)
+
+	ty := res.CoreConfigSchema().ImpliedType()
+	v, err := msgpack.Unmarshal(state.MsgPack, ty)
+	require.NoError(t, err)
+	js, err := ctyjson.Marshal(v, ty)
+	require.NoError(t, err)
+
+	var attrs map[string]any
+	require.NoError(t, json.Unmarshal(js, &attrs))
+	return attrs
+}
+
+func TestKVMVolume_UpgradeV0(t *testing.T) {
+	res := KVMVolume()
+	p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"tg_kvm_volume": res}}
+	require.NoError(t, p.InternalValidate())
+
+	resp, err := schema.NewGRPCProviderServer(p).UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "tg_kvm_volume",
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: stateFixture(t, "kvm_volume_v0.json")},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Diagnostics)
+
+	assert.Equal(t, map[string]any{
+		"id":             "data",
+		"node_id":        "d70e7d73-2a1c-4388-bbb1-08ca2fd39f48",
+		"name":           "data",
+		"size":           float64(10737418240),
+		"path":           "/var/lib/tg/kvm/data.qcow2",
+		"encrypted":      true,
+		"provision_type": "thin",
+		"device_type":    "disk",
+		"device_bus":     "virtio",
+	}, decodeState(t, res, resp.UpgradedState))
+}
@@ -2,7 +2,6 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 
@@ -72,9 +71,8 @@ func licenseCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.D
 
 	if tf.License == "" {
 		reply, err := tgc.RawGet(ctx, "/node/license?name="+tf.Name)
-		var verr *tg.ValidationError
 		switch {
-		case errors.As(err, &verr):
+		case tg.IsUnprocessable(err):
 			return diag.FromErr(fmt.Errorf("invalid license - usually this means the name is already taken"))
 		case err != nil:
 			return diag.FromErr(err)
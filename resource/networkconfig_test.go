@@ -3,6 +3,7 @@ package resource
 import (
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
@@ -14,10 +15,11 @@ func TestValidateNetworkConfigInterfaces(t *testing.T) {
 		interfaces []hcl.NetworkInterface
 		isCluster  bool
 		err        string
+		path       string
 	}{
 		{
 			name:       "node allows dhcp",
-			interfaces: []hcl.NetworkInterface{{NIC: "ens192", DHCP: true}},
+			interfaces: []hcl.NetworkInterface{{NIC: "ens192", DHCP: ptr(true)}},
 			isCluster:  false,
 		},
 		{
@@ -27,15 +29,29 @@ func TestValidateNetworkConfigInterfaces(t *testing.T) {
 		},
 		{
 			name:       "cluster rejects dhcp",
-			interfaces: []hcl.NetworkInterface{{NIC: "ens192", DHCP: true}},
+			interfaces: []hcl.NetworkInterface{{NIC: "ens192", DHCP: ptr(true)}},
 			isCluster:  true,
 			err:        `interface "ens192" cannot set dhcp = true when cluster_fqdn is set`,
+			path:       "interface.0.dhcp",
 		},
 		{
 			name:       "cluster rejects dhcp without nic",
-			interfaces: []hcl.NetworkInterface{{DHCP: true}},
+			interfaces: []hcl.NetworkInterface{{DHCP: ptr(true)}},
 			isCluster:  true,
 			err:        `interface "index 0" cannot set dhcp = true when cluster_fqdn is set`,
+			path:       "interface.0.dhcp",
+		},
+		{
+			name:       "cluster rejects dhcp on a later interface",
+			interfaces: []hcl.NetworkInterface{{NIC: "ens160"}, {DHCP: ptr(true)}},
+			isCluster:  true,
+			err:        `interface "index 1" cannot set dhcp = true when cluster_fqdn is set`,
+			path:       "interface.1.dhcp",
+		},
+		{
+			name:       "cluster allows dhcp explicitly off",
+			interfaces: []hcl.NetworkInterface{{NIC: "ens192", DHCP: ptr(false)}},
+			isCluster:  true,
 		},
 	}
 
@@ -49,6 +65,10 @@ func TestValidateNetworkConfigInterfaces(t *testing.T) {
 
 			require.Error(t, err)
 			assert.ErrorContains(t, err, tt.err)
+
+			var pathErr cty.PathError
+			require.ErrorAs(t, err, &pathErr)
+			assert.Equal(t, hcl.AttributePath(tt.path), pathErr.Path)
 		})
 	}
 }
@@ -3,9 +3,7 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,21 +13,6 @@ import (
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
-func nodeConnectorImport(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
-	parts := strings.SplitN(d.Id(), ":", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return nil, fmt.Errorf("expected import ID in form {node_id}:{connector_id}, got %q", d.Id())
-	}
-	if err := d.Set("node_id", parts[0]); err != nil {
-		return nil, err
-	}
-	if err := d.Set("connector_id", parts[1]); err != nil {
-		return nil, err
-	}
-	d.SetId(parts[1])
-	return []*schema.ResourceData{d}, nil
-}
-
 func nodeConnectorRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
@@ -41,9 +24,8 @@ func nodeConnectorRead(ctx context.Context, d *schema.ResourceData, meta any) di
 
 	var node tg.Node
 	err := tgc.Get(ctx, fmt.Sprintf("/node/%s", nodeID), &node)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -108,9 +90,7 @@ func NodeConnector() *schema.Resource {
 		ReadContext:   nodeConnectorRead,
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
-		Importer: &schema.ResourceImporter{
-			StateContext: nodeConnectorImport,
-		},
+		Importer:      majordomo.Importer(nodeConnectorRead, majordomo.AttributeID("connector_id"), "{node_id}:{connector_id}"),
 		Schema: map[string]*schema.Schema{
 			"connector_id": {
 				Description: "Connector unique ID. Computed after create.",
@@ -2,11 +2,11 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 	"github.com/trustgrid/terraform-provider-tg/validators"
 )
@@ -117,30 +117,25 @@ func NodeInterface() *schema.Resource {
 func (n *nodeInterface) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 	endpoint, isCluster := ifaceEndpoint(d)
-
-	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
+	nic := d.Get("nic").(string) //nolint: errcheck // ForceNew string field
+	iface, err := n.buildTGIface(d, nic)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	nic := d.Get("nic").(string) //nolint: errcheck // ForceNew string field
-	iface := n.buildTGIface(d, nic)
-
-	// Replace existing or append.
-	replaced := false
-	for i, existing := range nc.Interfaces {
-		if existing.NIC == nic {
-			nc.Interfaces[i] = iface
-			replaced = true
-			break
+	err = queueNetworkConfigChange(ctx, tgc, endpoint, isCluster, func(nc *tg.NetworkConfig) error {
+		// Replace existing or append.
+		for i, existing := range nc.Interfaces {
+			if existing.NIC == nic {
+				nc.Interfaces[i] = iface
+				return nil
+			}
 		}
-	}
-	if !replaced {
 		nc.Interfaces = append(nc.Interfaces, iface)
-	}
-
-	if err := putNetworkConfig(ctx, tgc, endpoint, isCluster, nc); err != nil {
-		return diag.FromErr(err)
+		return nil
+	})
+	if err != nil {
+		return networkConfigDiags(err)
 	}
 
 	d.SetId(encodeIfaceID(endpoint, nic))
@@ -153,9 +148,8 @@ func (n *nodeInterface) Read(ctx context.Context, d *schema.ResourceData, meta a
 	nic := d.Get("nic").(string) //nolint: errcheck // ForceNew string field
 
 	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -182,33 +176,42 @@ func (n *nodeInterface) Delete(ctx context.Context, d *schema.ResourceData, meta
 	endpoint, isCluster := ifaceEndpoint(d)
 	nic := d.Get("nic").(string) //nolint: errcheck // ForceNew string field
 
-	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	filtered := nc.Interfaces[:0]
-	for _, iface := range nc.Interfaces {
-		if iface.NIC != nic {
-			filtered = append(filtered, iface)
+	err := queueNetworkConfigChange(ctx, tgc, endpoint, isCluster, func(nc *tg.NetworkConfig) error {
+		filtered := nc.Interfaces[:0]
+		for _, iface := range nc.Interfaces {
+			if iface.NIC != nic {
+				filtered = append(filtered, iface)
+			}
 		}
-	}
-	nc.Interfaces = filtered
+		nc.Interfaces = filtered
+		return nil
+	})
+	return networkConfigDiags(err)
+}
 
-	return diag.FromErr(putNetworkConfig(ctx, tgc, endpoint, isCluster, nc))
+// nodeInterfaceZeroes holds the attributes whose zero values are sent when they're set explicitly
+// and left out otherwise, so the portal's defaults apply.
+type nodeInterfaceZeroes struct {
+	DHCP *bool `tf:"dhcp"`
+	MTU  *int  `tf:"mtu"`
 }
 
-func (n *nodeInterface) buildTGIface(d *schema.ResourceData, nic string) tg.NetworkInterface {
+func (n *nodeInterface) buildTGIface(d *schema.ResourceData, nic string) (tg.NetworkInterface, error) {
+	zeroes, err := hcl.DecodeResourceData[nodeInterfaceZeroes](d)
+	if err != nil {
+		return tg.NetworkInterface{}, err
+	}
+
 	iface := tg.NetworkInterface{
 		NIC:       nic,
-		DHCP:      d.Get("dhcp").(bool),         //nolint:errcheck // schema ensures TypeBool
-		Gateway:   d.Get("gateway").(string),    //nolint:errcheck // schema ensures TypeString
-		VRF:       d.Get("vrf").(string),        //nolint:errcheck // schema ensures TypeString
-		IP:        d.Get("ip").(string),         //nolint:errcheck // schema ensures TypeString
-		Mode:      d.Get("mode").(string),       //nolint:errcheck // schema ensures TypeString
-		Duplex:    d.Get("duplex").(string),     //nolint:errcheck // schema ensures TypeString
-		Speed:     d.Get("speed").(int),         //nolint:errcheck // schema ensures TypeInt
-		MTU:       d.Get("mtu").(int),           //nolint:errcheck // schema ensures TypeInt
+		DHCP:      zeroes.DHCP,
+		Gateway:   d.Get("gateway").(string), //nolint:errcheck // schema ensures TypeString
+		VRF:       d.Get("vrf").(string),     //nolint:errcheck // schema ensures TypeString
+		IP:        d.Get("ip").(string),      //nolint:errcheck // schema ensures TypeString
+		Mode:      d.Get("mode").(string),    //nolint:errcheck // schema ensures TypeString
+		Duplex:    d.Get("duplex").(string),  //nolint:errcheck // schema ensures TypeString
+		Speed:     d.Get("speed").(int),      //nolint:errcheck // schema ensures TypeInt
+		MTU:       zeroes.MTU,
 		ClusterIP: d.Get("cluster_ip").(string), //nolint:errcheck // schema ensures TypeString
 	}
 
@@ -218,20 +221,24 @@ func (n *nodeInterface) buildTGIface(d *schema.ResourceData, nic string) tg.Netw
 		}
 	}
 
-	return iface
+	return iface, nil
 }
 
 func (n *nodeInterface) setFromTG(d *schema.ResourceData, iface tg.NetworkInterface) error {
+	mtu := 0
+	if iface.MTU != nil {
+		mtu = *iface.MTU
+	}
 	fields := map[string]interface{}{
 		"nic":        iface.NIC,
-		"dhcp":       iface.DHCP,
+		"dhcp":       iface.DHCP != nil && *iface.DHCP,
 		"gateway":    iface.Gateway,
 		"vrf":        iface.VRF,
 		"ip":         iface.IP,
 		"mode":       iface.Mode,
 		"duplex":     iface.Duplex,
 		"speed":      iface.Speed,
-		"mtu":        iface.MTU,
+		"mtu":        mtu,
 		"cluster_ip": iface.ClusterIP,
 		"dns":        iface.DNS,
 	}
@@ -2,7 +2,7 @@ package resource
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -72,12 +72,6 @@ func NodeInterfaceRoute() *schema.Resource {
 func (r *nodeInterfaceRoute) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 	endpoint, isCluster := ifaceEndpoint(d)
-
-	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
 	nic := d.Get("nic").(string)    //nolint: errcheck // ForceNew string field
 	dest := d.Get("route").(string) //nolint: errcheck // ForceNew string field
 	route := tg.NetworkRoute{
@@ -86,31 +80,25 @@ func (r *nodeInterfaceRoute) Create(ctx context.Context, d *schema.ResourceData,
 		Description: d.Get("description").(string), //nolint: errcheck // typed schema field
 	}
 
-	// Find the interface and upsert the route.
-	found := false
-	for i, iface := range nc.Interfaces {
-		if iface.NIC == nic {
-			found = true
-			replaced := false
+	err := queueNetworkConfigChange(ctx, tgc, endpoint, isCluster, func(nc *tg.NetworkConfig) error {
+		// Find the interface and upsert the route.
+		for i, iface := range nc.Interfaces {
+			if iface.NIC != nic {
+				continue
+			}
 			for j, existing := range iface.Routes {
 				if existing.Route == dest {
 					nc.Interfaces[i].Routes[j] = route
-					replaced = true
-					break
+					return nil
 				}
 			}
-			if !replaced {
-				nc.Interfaces[i].Routes = append(nc.Interfaces[i].Routes, route)
-			}
-			break
+			nc.Interfaces[i].Routes = append(nc.Interfaces[i].Routes, route)
+			return nil
 		}
-	}
-	if !found {
-		return diag.Errorf("interface %q not found in network config; create a tg_node_interface resource for it first", nic)
-	}
-
-	if err := putNetworkConfig(ctx, tgc, endpoint, isCluster, nc); err != nil {
-		return diag.FromErr(err)
+		return fmt.Errorf("interface %q not found in network config; create a tg_node_interface resource for it first", nic)
+	})
+	if err != nil {
+		return networkConfigDiags(err)
 	}
 
 	d.SetId(encodeIfaceRouteID(endpoint, nic, dest))
@@ -124,9 +112,8 @@ func (r *nodeInterfaceRoute) Read(ctx context.Context, d *schema.ResourceData, m
 	dest := d.Get("route").(string) //nolint: errcheck // ForceNew string field
 
 	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -168,24 +155,21 @@ func (r *nodeInterfaceRoute) Delete(ctx context.Context, d *schema.ResourceData,
 	nic := d.Get("nic").(string)    //nolint: errcheck // ForceNew string field
 	dest := d.Get("route").(string) //nolint: errcheck // ForceNew string field
 
-	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	for i, iface := range nc.Interfaces {
-		if iface.NIC != nic {
-			continue
-		}
-		filtered := iface.Routes[:0]
-		for _, route := range iface.Routes {
-			if route.Route != dest {
-				filtered = append(filtered, route)
+	err := queueNetworkConfigChange(ctx, tgc, endpoint, isCluster, func(nc *tg.NetworkConfig) error {
+		for i, iface := range nc.Interfaces {
+			if iface.NIC != nic {
+				continue
+			}
+			filtered := iface.Routes[:0]
+			for _, route := range iface.Routes {
+				if route.Route != dest {
+					filtered = append(filtered, route)
+				}
 			}
+			nc.Interfaces[i].Routes = filtered
+			break
 		}
-		nc.Interfaces[i].Routes = filtered
-		break
-	}
-
-	return diag.FromErr(putNetworkConfig(ctx, tgc, endpoint, isCluster, nc))
+		return nil
+	})
+	return networkConfigDiags(err)
 }
@@ -2,7 +2,7 @@ package resource
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -112,42 +112,29 @@ func NodeInterfaceVLAN() *schema.Resource {
 func (n *nodeInterfaceVLAN) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 	endpoint, isCluster := ifaceEndpoint(d)
-
-	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
 	nic := d.Get("nic").(string)     //nolint: errcheck // ForceNew string field
 	vlanID := d.Get("vlan_id").(int) //nolint: errcheck // ForceNew int field
 	sub := n.buildTGSub(d, vlanID)
 
-	// Find or create the parent interface.
-	found := false
-	for i, iface := range nc.Interfaces {
-		if iface.NIC != nic {
-			continue
-		}
-		found = true
-		replaced := false
-		for j, existing := range iface.SubInterfaces {
-			if existing.VLANID == vlanID {
-				nc.Interfaces[i].SubInterfaces[j] = sub
-				replaced = true
-				break
+	err := queueNetworkConfigChange(ctx, tgc, endpoint, isCluster, func(nc *tg.NetworkConfig) error {
+		// Find the parent interface and upsert the sub-interface.
+		for i, iface := range nc.Interfaces {
+			if iface.NIC != nic {
+				continue
+			}
+			for j, existing := range iface.SubInterfaces {
+				if existing.VLANID == vlanID {
+					nc.Interfaces[i].SubInterfaces[j] = sub
+					return nil
+				}
 			}
-		}
-		if !replaced {
 			nc.Interfaces[i].SubInterfaces = append(nc.Interfaces[i].SubInterfaces, sub)
+			return nil
 		}
-		break
-	}
-	if !found {
-		return diag.Errorf("interface %q not found in network config; create a tg_node_interface resource for it first", nic)
-	}
-
-	if err := putNetworkConfig(ctx, tgc, endpoint, isCluster, nc); err != nil {
-		return diag.FromErr(err)
+		return fmt.Errorf("interface %q not found in network config; create a tg_node_interface resource for it first", nic)
+	})
+	if err != nil {
+		return networkConfigDiags(err)
 	}
 
 	d.SetId(encodeIfaceVLANID(endpoint, nic, vlanID))
@@ -161,9 +148,8 @@ func (n *nodeInterfaceVLAN) Read(ctx context.Context, d *schema.ResourceData, me
 	vlanID := d.Get("vlan_id").(int) //nolint: errcheck // ForceNew int field
 
 	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -199,26 +185,23 @@ func (n *nodeInterfaceVLAN) Delete(ctx context.Context, d *schema.ResourceData,
 	nic := d.Get("nic").(string)     //nolint: errcheck // ForceNew string field
 	vlanID := d.Get("vlan_id").(int) //nolint: errcheck // ForceNew int field
 
-	nc, err := getNetworkConfig(ctx, tgc, endpoint, isCluster)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	for i, iface := range nc.Interfaces {
-		if iface.NIC != nic {
-			continue
-		}
-		filtered := iface.SubInterfaces[:0]
-		for _, sub := range iface.SubInterfaces {
-			if sub.VLANID != vlanID {
-				filtered = append(filtered, sub)
+	err := queueNetworkConfigChange(ctx, tgc, endpoint, isCluster, func(nc *tg.NetworkConfig) error {
+		for i, iface := range nc.Interfaces {
+			if iface.NIC != nic {
+				continue
+			}
+			filtered := iface.SubInterfaces[:0]
+			for _, sub := range iface.SubInterfaces {
+				if sub.VLANID != vlanID {
+					filtered = append(filtered, sub)
+				}
 			}
+			nc.Interfaces[i].SubInterfaces = filtered
+			break
 		}
-		nc.Interfaces[i].SubInterfaces = filtered
-		break
-	}
-
-	return diag.FromErr(putNetworkConfig(ctx, tgc, endpoint, isCluster, nc))
+		return nil
+	})
+	return networkConfigDiags(err)
 }
 
 func (n *nodeInterfaceVLAN) buildTGSub(d *schema.ResourceData, vlanID int) tg.SubInterface {
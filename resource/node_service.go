@@ -3,9 +3,7 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,21 +13,6 @@ import (
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
-func nodeServiceImport(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
-	parts := strings.SplitN(d.Id(), ":", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return nil, fmt.Errorf("expected import ID in form {node_id}:{service_id}, got %q", d.Id())
-	}
-	if err := d.Set("node_id", parts[0]); err != nil {
-		return nil, err
-	}
-	if err := d.Set("service_id", parts[1]); err != nil {
-		return nil, err
-	}
-	d.SetId(parts[1])
-	return []*schema.ResourceData{d}, nil
-}
-
 func nodeServiceRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
@@ -41,9 +24,8 @@ func nodeServiceRead(ctx context.Context, d *schema.ResourceData, meta any) diag
 
 	var node tg.Node
 	err := tgc.Get(ctx, fmt.Sprintf("/node/%s", nodeID), &node)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -108,9 +90,7 @@ func NodeService() *schema.Resource {
 		ReadContext:   nodeServiceRead,
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
-		Importer: &schema.ResourceImporter{
-			StateContext: nodeServiceImport,
-		},
+		Importer:      majordomo.Importer(nodeServiceRead, majordomo.AttributeID("service_id"), "{node_id}:{service_id}"),
 		Schema: map[string]*schema.Schema{
 			"service_id": {
 				Description: "Service unique ID. Computed after create.",
@@ -166,3 +146,54 @@ func NodeService() *schema.Resource {
 		},
 	}
 }
+
+// NodeServiceMovers returns the StateMovers for tg_node_service, which move legacy tg_service
+// state for a node service with a `moved` block.
+func NodeServiceMovers() []majordomo.StateMover {
+	return []majordomo.StateMover{
+		majordomo.NewStateMover("tg_service", moveNodeServiceFromService),
+	}
+}
+
+// moveNodeServiceFromService moves tg_service state to tg_node_service. The V2 upgrade rekeys a
+// node's services, so once the node is on V2 the service is found by name instead of its V1 ID.
+func moveNodeServiceFromService(ctx context.Context, meta any, id string, old hcl.Service) (string, hcl.NodeService, error) {
+	if old.NodeID == "" {
+		return "", hcl.NodeService{}, fmt.Errorf("tg_service %s is a cluster service; move it to tg_cluster_service instead", old.Name)
+	}
+
+	tgc, ok := meta.(*tg.Client)
+	if !ok {
+		return "", hcl.NodeService{}, fmt.Errorf("the provider must be configured to move tg_service %s", old.Name)
+	}
+
+	var node tg.Node
+	if err := tgc.Get(ctx, fmt.Sprintf("/node/%s", old.NodeID), &node); err != nil {
+		return "", hcl.NodeService{}, fmt.Errorf("looking up node %s: %w", old.NodeID, err)
+	}
+
+	var found *tg.Service
+	for i, svc := range node.Config.Services.Services {
+		if svc.ID == id {
+			found = &node.Config.Services.Services[i]
+			break
+		}
+		if found == nil && svc.Name == old.Name {
+			found = &node.Config.Services.Services[i]
+		}
+	}
+	if found == nil {
+		return "", hcl.NodeService{}, fmt.Errorf("node %s has no service %s (id %s)", old.NodeID, old.Name, id)
+	}
+
+	return found.ID, hcl.NodeService{
+		ServiceID:   found.ID,
+		NodeID:      old.NodeID,
+		Name:        old.Name,
+		Protocol:    old.Protocol,
+		Host:        old.Host,
+		Port:        old.Port,
+		Description: old.Description,
+		Enabled:     true,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package resource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+const serviceNodeID = "d70e7d73-2a1c-4388-bbb1-08ca2fd39f48"
+
+// moveService moves the recorded tg_service state in fixture to tg_node_service, against a node
+// whose services config is servicesJSON.
+func moveService(t *testing.T, fixture string, servicesJSON string) (*schema.Resource, *tfprotov5.MoveResourceStateResponse) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/"+serviceNodeID, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"uid":"` + serviceNodeID + `","config":{"services":` + servicesJSON + `}}`))
+	})
+
+	target := NodeService()
+	p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{
+		"tg_service":      Service(),
+		"tg_node_service": target,
+	}}
+	require.NoError(t, p.InternalValidate())
+	p.SetMeta(newTestClient(t, mux, tg.ClientParams{}))
+
+	srv := majordomo.ProviderServer(p, map[string][]majordomo.StateMover{"tg_node_service": NodeServiceMovers()})
+	resp, err := srv.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceProviderAddress: "registry.terraform.io/trustgrid/tg",
+		SourceTypeName:        "tg_service",
+		SourceSchemaVersion:   0,
+		SourceState:           &tfprotov5.RawState{JSON: stateFixture(t, fixture)},
+		TargetTypeName:        "tg_node_service",
+	})
+	require.NoError(t, err)
+	return target, resp
+}
+
+func TestNodeService_MoveFromService(t *testing.T) {
+	// The V2 upgrade rekeyed the service, so it's found by name.
+	target, resp := moveService(t, "service_v0_node.json", `{"items":{
+		"5e1d2c3b-4a59-4867-9f8e-0a1b2c3d4e5f":{"name":"https-forwarder","enabled":true,"host":"10.20.30.40","port":443,"protocol":"tcp"},
+		"a0b1c2d3-e4f5-4a6b-8c7d-9e0f1a2b3c4d":{"name":"ssh","enabled":true,"host":"10.20.30.41","port":22,"protocol":"tcp"}
+	}}`)
+	require.Empty(t, resp.Diagnostics)
+
+	assert.Equal(t, map[string]any{
+		"id":               "5e1d2c3b-4a59-4867-9f8e-0a1b2c3d4e5f",
+		"service_id":       "5e1d2c3b-4a59-4867-9f8e-0a1b2c3d4e5f",
+		"node_id":          serviceNodeID,
+		"name":             "https-forwarder",
+		"protocol":         "tcp",
+		"host":             "10.20.30.40",
+		"port":             float64(443),
+		"description":      "HTTPS to the app tier",
+		"enabled":          true,
+		"source_interface": "",
+	}, decodeState(t, target, resp.TargetState))
+}
+
+func TestNodeService_MoveFromServiceKeepsID(t *testing.T) {
+	// A service that's still under its V1 ID keeps it, even if another has the same name.
+	target, resp := moveService(t, "service_v0_node.json", `{"services":[
+		{"id":"1f2e3d4c-5b6a-4798-8a9b-0c1d2e3f4a5b","name":"https-forwarder","host":"10.20.30.50","port":443,"protocol":"tcp"},
+		{"id":"8f0b7c52-31d4-4f0e-9a3e-6d2b1c9e4a70","name":"https-forwarder","host":"10.20.30.40","port":443,"protocol":"tcp"}
+	]}`)
+	require.Empty(t, resp.Diagnostics)
+
+	state := decodeState(t, target, resp.TargetState)
+	assert.Equal(t, "8f0b7c52-31d4-4f0e-9a3e-6d2b1c9e4a70", state["id"])
+	assert.Equal(t, "8f0b7c52-31d4-4f0e-9a3e-6d2b1c9e4a70", state["service_id"])
+}
+
+func TestNodeService_MoveFromServiceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		services string
+		detail   string
+	}{
+		{
+			name:     "cluster service",
+			fixture:  "service_v0_cluster.json",
+			services: `{"items":{}}`,
+			detail:   "tg_service https-forwarder is a cluster service; move it to tg_cluster_service instead",
+		},
+		{
+			name:     "missing service",
+			fixture:  "service_v0_node.json",
+			services: `{"items":{"a0b1c2d3-e4f5-4a6b-8c7d-9e0f1a2b3c4d":{"name":"ssh","host":"10.20.30.41","port":22,"protocol":"tcp"}}}`,
+			detail:   "node " + serviceNodeID + " has no service https-forwarder (id 8f0b7c52-31d4-4f0e-9a3e-6d2b1c9e4a70)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp := moveService(t, tt.fixture, tt.services)
+			require.Len(t, resp.Diagnostics, 1)
+			assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+			assert.Equal(t, "moving tg_service to tg_node_service", resp.Diagnostics[0].Summary)
+			assert.Equal(t, tt.detail, resp.Diagnostics[0].Detail)
+			assert.Nil(t, resp.TargetState)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package resource
 
 import (
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
 	"github.com/trustgrid/terraform-provider-tg/majordomo"
@@ -15,6 +17,11 @@ func NodeState() *schema.Resource {
 			ID: func(n hcl.Node) string {
 				return n.UID
 			},
+			ImportID: []string{"{node_id}"},
+			// The portal applies state changes asynchronously, so wait until it reports the new one.
+			Converged: func(n hcl.Node, s tg.NodeState) bool {
+				return s.State == n.ToTG().State
+			},
 		})
 
 	return &schema.Resource{
@@ -24,6 +31,12 @@ func NodeState() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Noop,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -0,0 +1,52 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
+	"github.com/trustgrid/terraform-provider-tg/validators"
+)
+
+// isNodeID reports whether an import ID is a node UUID.
+func isNodeID(id string) bool {
+	_, errs := validation.IsUUID(id, "id")
+	return len(errs) == 0
+}
+
+// isClusterFQDN reports whether an import ID is a cluster FQDN.
+func isClusterFQDN(id string) bool {
+	_, errs := validators.IsHostname(id, "id")
+	return len(errs) == 0
+}
+
+// nodeOrClusterImporter imports a node or cluster config singleton from either a node UUID or a
+// cluster FQDN. It works out which one it was given and fills in node_id or cluster_fqdn to
+// match, so the read that follows loads the real config and the first plan shows the real diff.
+// Resources that only exist on nodes pass nodeOnly to reject cluster FQDNs up front. `id` sets the
+// resource ID as in majordomo.Importer; nil keeps the import ID.
+func nodeOrClusterImporter(read schema.ReadContextFunc, id func(*schema.ResourceData) (string, error), nodeOnly bool) *schema.ResourceImporter {
+	node := majordomo.Importer(read, id, "{node_id}")
+	cluster := majordomo.Importer(read, id, "{cluster_fqdn}")
+
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+			importID := d.Id()
+
+			switch {
+			case isNodeID(importID):
+				return node.StateContext(ctx, d, meta)
+			case nodeOnly && isClusterFQDN(importID):
+				return nil, fmt.Errorf("unexpected import ID %q: this resource only applies to nodes, expected a node UUID", importID)
+			case nodeOnly:
+				return nil, fmt.Errorf("unexpected import ID %q, expected a node UUID", importID)
+			case isClusterFQDN(importID):
+				return cluster.StateContext(ctx, d, meta)
+			default:
+				return nil, fmt.Errorf("unexpected import ID %q, expected a node UUID or a cluster FQDN", importID)
+			}
+		},
+	}
+}
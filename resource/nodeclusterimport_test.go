@@ -0,0 +1,90 @@
+package resource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+const testImportNodeID = "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d"
+
+func importTaggingClient(t *testing.T) *tg.Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/"+testImportNodeID, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"uid":"`+testImportNodeID+`","tags":{"env":"prod"}}`)
+	})
+	mux.HandleFunc("GET /api/cluster/edge.example.com", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"name":"edge","fqdn":"edge.example.com","tags":{"env":"dev"}}`)
+	})
+	return newTestClient(t, mux, tg.ClientParams{})
+}
+
+func TestNodeOrClusterImporter_Node(t *testing.T) {
+	tgc := importTaggingClient(t)
+	r := Tagging()
+
+	d := r.TestResourceData()
+	d.SetId(testImportNodeID)
+	states, err := r.Importer.StateContext(context.Background(), d, tgc)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+
+	assert.Equal(t, testImportNodeID, states[0].Id())
+	assert.Equal(t, testImportNodeID, states[0].Get("node_id"))
+	assert.Empty(t, states[0].Get("cluster_fqdn"))
+	assert.Equal(t, map[string]any{"env": "prod"}, states[0].Get("tags"))
+}
+
+func TestNodeOrClusterImporter_Cluster(t *testing.T) {
+	tgc := importTaggingClient(t)
+	r := Tagging()
+
+	d := r.TestResourceData()
+	d.SetId("edge.example.com")
+	states, err := r.Importer.StateContext(context.Background(), d, tgc)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+
+	assert.Equal(t, "edge.example.com", states[0].Id())
+	assert.Equal(t, "edge.example.com", states[0].Get("cluster_fqdn"))
+	assert.Empty(t, states[0].Get("node_id"))
+	assert.Equal(t, map[string]any{"env": "dev"}, states[0].Get("tags"))
+}
+
+func TestClusterImport(t *testing.T) {
+	tgc := importTaggingClient(t)
+
+	d, err := importState(t, Cluster(), tgc, "edge.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "edge.example.com", d.Id())
+	assert.Equal(t, "edge", d.Get("name"))
+	assert.Equal(t, "edge.example.com", d.Get("fqdn"))
+}
+
+func TestNodeOrClusterImporter_BadIDs(t *testing.T) {
+	d := Tagging().TestResourceData()
+	d.SetId("not an id")
+	_, err := Tagging().Importer.StateContext(context.Background(), d, nil)
+	assert.EqualError(t, err, `unexpected import ID "not an id", expected a node UUID or a cluster FQDN`)
+
+	d = SNMP().TestResourceData()
+	d.SetId("edge.example.com")
+	_, err = SNMP().Importer.StateContext(context.Background(), d, nil)
+	assert.EqualError(t, err, `unexpected import ID "edge.example.com": this resource only applies to nodes, expected a node UUID`)
+}
+
+func TestCPULimitsImportID(t *testing.T) {
+	d := CPULimits().TestResourceData()
+	require.NoError(t, d.Set("node_id", testImportNodeID))
+
+	id, err := cpuLimitsImportID(d)
+	require.NoError(t, err)
+	assert.Equal(t, "cpu_limits_"+testImportNodeID, id)
+}
@@ -2,8 +2,15 @@ package resource
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
@@ -21,6 +28,22 @@ func ifaceEndpoint(d *schema.ResourceData) (string, bool) {
 	return id, true
 }
 
+// endpointTarget returns the client lock target for a node or cluster endpoint.
+func endpointTarget(id string, isCluster bool) string {
+	if isCluster {
+		return tg.ClusterTarget(id)
+	}
+	return tg.NodeTarget(id)
+}
+
+// nodeOrClusterTarget returns the client lock target for whichever of nodeID and clusterFQDN is set.
+func nodeOrClusterTarget(nodeID, clusterFQDN string) string {
+	if nodeID != "" {
+		return tg.NodeTarget(nodeID)
+	}
+	return tg.ClusterTarget(clusterFQDN)
+}
+
 // getNetworkConfig fetches the current full NetworkConfig for a node or cluster.
 func getNetworkConfig(ctx context.Context, tgc *tg.Client, id string, isCluster bool) (tg.NetworkConfig, error) {
 	if isCluster {
@@ -50,6 +73,179 @@ func putNetworkConfig(ctx context.Context, tgc *tg.Client, id string, isCluster
 	return err
 }
 
+// networkConfigAttempts is how many times updateNetworkConfig re-reads and reapplies a change
+// when the network config keeps changing underneath it.
+const networkConfigAttempts = 3
+
+// errNetworkConfigConflict is returned when the network config changed between the read and
+// the write on every attempt.
+var errNetworkConfigConflict = errors.New("network config changed while it was being updated")
+
+// fetchNetworkConfig reads the network config past the client's read cache, along with its
+// version: a hash of the config as the portal sent it, so edits to settings tg.NetworkConfig
+// doesn't model are noticed too.
+func fetchNetworkConfig(ctx context.Context, tgc *tg.Client, id string, isCluster bool) (tg.NetworkConfig, string, error) {
+	url := "/node/" + id
+	if isCluster {
+		url = "/cluster/" + id
+	}
+
+	var doc struct {
+		Config struct {
+			Network json.RawMessage `json:"network"`
+		} `json:"config"`
+	}
+	if err := tgc.Get(tg.WithoutCache(ctx), url, &doc); err != nil {
+		return tg.NetworkConfig{}, "", err
+	}
+
+	var nc tg.NetworkConfig
+	if len(doc.Config.Network) > 0 {
+		if err := json.Unmarshal(doc.Config.Network, &nc); err != nil {
+			return tg.NetworkConfig{}, "", fmt.Errorf("error decoding network config of %s: %w", id, err)
+		}
+	}
+	sum := sha256.Sum256(doc.Config.Network)
+	return nc, hex.EncodeToString(sum[:]), nil
+}
+
+// updateNetworkConfig applies mutate to the current network config of a node or cluster and
+// writes it back. The portal has no conditional PUT, so the config is re-read right before
+// the write; if it changed since mutate saw it (another apply or someone in the portal), the
+// change is reapplied to the fresh copy. The same goes for a write the portal rejects with a
+// 409 because the config changed in between. Errors from mutate are returned as-is.
+func updateNetworkConfig(ctx context.Context, tgc *tg.Client, id string, isCluster bool, mutate func(*tg.NetworkConfig) error) error {
+	for attempt := 1; attempt <= networkConfigAttempts; attempt++ {
+		nc, version, err := fetchNetworkConfig(ctx, tgc, id, isCluster)
+		if err != nil {
+			return err
+		}
+
+		if err := mutate(&nc); err != nil {
+			return err
+		}
+
+		_, current, err := fetchNetworkConfig(ctx, tgc, id, isCluster)
+		if err != nil {
+			return err
+		}
+		if current != version {
+			tflog.Warn(ctx, "network config changed concurrently, reapplying", map[string]any{
+				"endpoint": id,
+				"attempt":  attempt,
+			})
+			continue
+		}
+
+		err = putNetworkConfig(ctx, tgc, id, isCluster, nc)
+		if tg.IsConflict(err) {
+			tflog.Warn(ctx, "network config write conflicted, reapplying", map[string]any{
+				"endpoint": id,
+				"attempt":  attempt,
+			})
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("%w: %s was modified on each of %d attempts", errNetworkConfigConflict, id, networkConfigAttempts)
+}
+
+// networkConfigDiags turns an updateNetworkConfig error into diagnostics, explaining conflicts.
+func networkConfigDiags(err error) diag.Diagnostics {
+	if errors.Is(err, errNetworkConfigConflict) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Concurrent network config edit",
+			Detail:   err.Error() + ". Another Terraform apply or a portal user is editing the same network config; re-run the apply once they're done.",
+		}}
+	}
+	return diag.FromErr(err)
+}
+
+// networkConfigBatchWindow is how long a network config change waits for changes to the same
+// node or cluster from other resources in the apply, so they all go out in one PUT.
+const networkConfigBatchWindow = 250 * time.Millisecond
+
+// networkConfigChange is one resource's edit to a node or cluster network config.
+type networkConfigChange struct {
+	tgc       *tg.Client
+	id        string
+	isCluster bool
+	mutate    func(*tg.NetworkConfig) error
+}
+
+var networkConfigBatches = tg.NewBatcher(networkConfigBatchWindow, flushNetworkConfig)
+
+// errNothingToWrite stops a batched update when every change in it failed.
+var errNothingToWrite = errors.New("no network config changes to write")
+
+// queueNetworkConfigChange applies mutate to the network config of a node or cluster, merged
+// with the changes other resources queue for it at the same time. Errors from mutate only
+// fail the resource that queued it; errors writing the config fail every change in the batch.
+func queueNetworkConfigChange(ctx context.Context, tgc *tg.Client, id string, isCluster bool, mutate func(*tg.NetworkConfig) error) error {
+	return networkConfigBatches.Do(ctx, endpointTarget(id, isCluster), networkConfigChange{
+		tgc:       tgc,
+		id:        id,
+		isCluster: isCluster,
+		mutate:    mutate,
+	})
+}
+
+// flushNetworkConfig writes a batch of changes to one node or cluster with a single update.
+func flushNetworkConfig(ctx context.Context, target string, changes []networkConfigChange) []error {
+	first := changes[0]
+	errs := make([]error, len(changes))
+
+	unlock := first.tgc.LockTarget(target)
+	defer unlock()
+
+	tflog.Debug(ctx, "writing batched network config", map[string]any{
+		"target":  target,
+		"changes": len(changes),
+	})
+
+	err := updateNetworkConfig(ctx, first.tgc, first.id, first.isCluster, func(nc *tg.NetworkConfig) error {
+		applied := 0
+		for i, c := range changes {
+			errs[i] = applyNetworkConfigChange(nc, c.mutate)
+			if errs[i] == nil {
+				applied++
+			}
+		}
+		if applied == 0 {
+			return errNothingToWrite
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNothingToWrite) {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = err
+			}
+		}
+	}
+	return errs
+}
+
+// applyNetworkConfigChange runs mutate against nc, leaving nc untouched if it fails so one
+// resource's error doesn't leak half a change into the rest of the batch.
+func applyNetworkConfigChange(nc *tg.NetworkConfig, mutate func(*tg.NetworkConfig) error) error {
+	b, err := json.Marshal(nc)
+	if err != nil {
+		return err
+	}
+	var scratch tg.NetworkConfig
+	if err := json.Unmarshal(b, &scratch); err != nil {
+		return err
+	}
+	if err := mutate(&scratch); err != nil {
+		return err
+	}
+	*nc = scratch
+	return nil
+}
+
 // encodeIfaceID builds "{endpoint}:{nic}".
 func encodeIfaceID(endpoint, nic string) string {
 	return endpoint + ":" + nic
@@ -0,0 +1,269 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// fakeNetworkPortal serves a single node's network config. onGet runs before every read and
+// can change the stored config to simulate someone else editing it.
+type fakeNetworkPortal struct {
+	mu    sync.Mutex
+	nc    tg.NetworkConfig
+	gets  int
+	puts  []tg.NetworkConfig
+	onGet func(n int, nc *tg.NetworkConfig)
+	// unmodeled is a network setting tg.NetworkConfig has no field for. It's sent when set.
+	unmodeled string
+	// conflicts is how many writes are rejected with a 409 before one is accepted.
+	conflicts int
+}
+
+// newTestClient returns a client talking to mux, which gets the org lookup NewClient makes.
+func newTestClient(t *testing.T, mux *http.ServeMux, params tg.ClientParams) *tg.Client {
+	t.Helper()
+	return newOrgTestClient(t, "example.trustgrid.io", mux, params)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// newOrgTestClient is newTestClient for an org with the given domain.
+func newOrgTestClient(t *testing.T, domain string, mux *http.ServeMux, params tg.ClientParams) *tg.Client {
+	t.Helper()
+
+	mux.HandleFunc("GET /api/org/mine", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"uid":"org-1","domain":%q}`, domain)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	params.APIKey = "key"
+	params.APISecret = "secret"
+	params.BaseURL = srv.URL + "/api/"
+	params.HTTPClient = srv.Client()
+
+	tgc, err := tg.NewClient(context.Background(), params)
+	require.NoError(t, err)
+	return tgc
+}
+
+func (p *fakeNetworkPortal) client(t *testing.T, nodeID string) *tg.Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/"+nodeID, func(w http.ResponseWriter, _ *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.gets++
+		if p.onGet != nil {
+			p.onGet(p.gets, &p.nc)
+		}
+		n := tg.Node{UID: nodeID}
+		n.Config.Network = p.nc
+		if p.unmodeled == "" {
+			_ = json.NewEncoder(w).Encode(n)
+			return
+		}
+
+		network, _ := json.Marshal(p.nc)
+		network = append(network[:len(network)-1], fmt.Sprintf(`,"unmodeled":%q}`, p.unmodeled)...)
+		_, _ = fmt.Fprintf(w, `{"uid":%q,"config":{"network":%s}}`, nodeID, network)
+	})
+	mux.HandleFunc("PUT /api/node/"+nodeID+"/config/network", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		var nc tg.NetworkConfig
+		if err := json.NewDecoder(r.Body).Decode(&nc); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if p.conflicts > 0 {
+			p.conflicts--
+			http.Error(w, `{"message":"network config was modified"}`, http.StatusConflict)
+			return
+		}
+		p.nc = nc
+		p.puts = append(p.puts, nc)
+		_, _ = io.WriteString(w, `{}`)
+	})
+
+	return newTestClient(t, mux, tg.ClientParams{CacheTTL: tg.DefaultCacheTTL})
+}
+
+func addNIC(nic string) func(*tg.NetworkConfig) error {
+	return func(nc *tg.NetworkConfig) error {
+		nc.Interfaces = append(nc.Interfaces, tg.NetworkInterface{NIC: nic})
+		return nil
+	}
+}
+
+func nics(nc tg.NetworkConfig) []string {
+	out := make([]string, 0, len(nc.Interfaces))
+	for _, iface := range nc.Interfaces {
+		out = append(out, iface.NIC)
+	}
+	return out
+}
+
+func TestUpdateNetworkConfig_NoConflict(t *testing.T) {
+	p := &fakeNetworkPortal{nc: tg.NetworkConfig{Interfaces: []tg.NetworkInterface{{NIC: "ens160"}}}}
+	tgc := p.client(t, "node-1")
+
+	require.NoError(t, updateNetworkConfig(context.Background(), tgc, "node-1", false, addNIC("ens192")))
+
+	require.Len(t, p.puts, 1)
+	assert.Equal(t, []string{"ens160", "ens192"}, nics(p.nc))
+	assert.Equal(t, 2, p.gets)
+}
+
+func TestUpdateNetworkConfig_ReappliesAfterConcurrentEdit(t *testing.T) {
+	p := &fakeNetworkPortal{nc: tg.NetworkConfig{Interfaces: []tg.NetworkInterface{{NIC: "ens160"}}}}
+	p.onGet = func(n int, nc *tg.NetworkConfig) {
+		// Someone adds an interface between our first read and the pre-write check.
+		if n == 2 {
+			nc.Interfaces = append(nc.Interfaces, tg.NetworkInterface{NIC: "ens224"})
+		}
+	}
+	tgc := p.client(t, "node-1")
+
+	require.NoError(t, updateNetworkConfig(context.Background(), tgc, "node-1", false, addNIC("ens192")))
+
+	require.Len(t, p.puts, 1)
+	assert.Equal(t, []string{"ens160", "ens224", "ens192"}, nics(p.nc), "the concurrent edit must survive")
+	assert.Equal(t, 4, p.gets)
+}
+
+func TestUpdateNetworkConfig_NoticesUnmodeledEdits(t *testing.T) {
+	p := &fakeNetworkPortal{nc: tg.NetworkConfig{Interfaces: []tg.NetworkInterface{{NIC: "ens160"}}}, unmodeled: "a"}
+	p.onGet = func(n int, _ *tg.NetworkConfig) {
+		// Someone changes a setting the provider doesn't know about between the read and the check.
+		if n == 2 {
+			p.unmodeled = "b"
+		}
+	}
+	tgc := p.client(t, "node-1")
+
+	require.NoError(t, updateNetworkConfig(context.Background(), tgc, "node-1", false, addNIC("ens192")))
+
+	require.Len(t, p.puts, 1)
+	assert.Equal(t, 4, p.gets, "the change is reapplied to a fresh read")
+}
+
+func TestUpdateNetworkConfig_PersistentConflict(t *testing.T) {
+	p := &fakeNetworkPortal{}
+	p.onGet = func(n int, nc *tg.NetworkConfig) {
+		mtu := 1400 + n
+		nc.Interfaces = []tg.NetworkInterface{{NIC: "ens160", MTU: &mtu}}
+	}
+	tgc := p.client(t, "node-1")
+
+	err := updateNetworkConfig(context.Background(), tgc, "node-1", false, addNIC("ens192"))
+	require.ErrorIs(t, err, errNetworkConfigConflict)
+	assert.Empty(t, p.puts)
+	assert.Equal(t, 2*networkConfigAttempts, p.gets)
+
+	diags := networkConfigDiags(err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Concurrent network config edit", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "re-run the apply")
+}
+
+func TestUpdateNetworkConfig_ReappliesAfterRejectedWrite(t *testing.T) {
+	p := &fakeNetworkPortal{nc: tg.NetworkConfig{Interfaces: []tg.NetworkInterface{{NIC: "ens160"}}}, conflicts: 1}
+	tgc := p.client(t, "node-1")
+
+	require.NoError(t, updateNetworkConfig(context.Background(), tgc, "node-1", false, addNIC("ens192")))
+
+	require.Len(t, p.puts, 1)
+	assert.Equal(t, []string{"ens160", "ens192"}, nics(p.nc))
+	assert.Equal(t, 4, p.gets, "the change is reapplied to a fresh read")
+}
+
+func TestUpdateNetworkConfig_PersistentRejectedWrite(t *testing.T) {
+	p := &fakeNetworkPortal{conflicts: networkConfigAttempts}
+	tgc := p.client(t, "node-1")
+
+	err := updateNetworkConfig(context.Background(), tgc, "node-1", false, addNIC("ens192"))
+	require.ErrorIs(t, err, errNetworkConfigConflict)
+	assert.Empty(t, p.puts)
+}
+
+func TestUpdateNetworkConfig_MutateError(t *testing.T) {
+	p := &fakeNetworkPortal{}
+	tgc := p.client(t, "node-1")
+
+	err := updateNetworkConfig(context.Background(), tgc, "node-1", false, func(*tg.NetworkConfig) error {
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, p.puts)
+}
+
+func TestQueueNetworkConfigChange_OnePutPerBatch(t *testing.T) {
+	p := &fakeNetworkPortal{nc: tg.NetworkConfig{Interfaces: []tg.NetworkInterface{{NIC: "ens160"}}}}
+	tgc := p.client(t, "node-1")
+
+	addRoute := func(nic, dest string) func(*tg.NetworkConfig) error {
+		return func(nc *tg.NetworkConfig) error {
+			for i, iface := range nc.Interfaces {
+				if iface.NIC == nic {
+					nc.Interfaces[i].Routes = append(nc.Interfaces[i].Routes, tg.NetworkRoute{Route: dest})
+					return nil
+				}
+			}
+			// Leave a partial edit behind to check it's discarded.
+			nc.Interfaces = append(nc.Interfaces, tg.NetworkInterface{NIC: "partial"})
+			return fmt.Errorf("interface %q not found", nic)
+		}
+	}
+
+	changes := []func(*tg.NetworkConfig) error{
+		addRoute("ens160", "10.0.0.0/24"),
+		addRoute("ens999", "10.1.0.0/24"),
+		addRoute("ens160", "10.2.0.0/24"),
+	}
+	errs := make([]error, len(changes))
+	var wg sync.WaitGroup
+	for i, mutate := range changes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = queueNetworkConfigChange(context.Background(), tgc, "node-1", false, mutate)
+		}()
+	}
+	wg.Wait()
+
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], `interface "ens999" not found`)
+	assert.NoError(t, errs[2])
+
+	require.Len(t, p.puts, 1)
+	assert.Equal(t, []string{"ens160"}, nics(p.nc))
+	assert.ElementsMatch(t,
+		[]tg.NetworkRoute{{Route: "10.0.0.0/24"}, {Route: "10.2.0.0/24"}},
+		p.nc.Interfaces[0].Routes)
+}
+
+func TestQueueNetworkConfigChange_AllFailed(t *testing.T) {
+	p := &fakeNetworkPortal{}
+	tgc := p.client(t, "node-1")
+
+	err := queueNetworkConfigChange(context.Background(), tgc, "node-1", false, func(*tg.NetworkConfig) error {
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, p.puts)
+}
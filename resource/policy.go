@@ -21,6 +21,7 @@ func Policy() *schema.Resource {
 			RemoteID: func(user tg.Policy) string {
 				return user.Name
 			},
+			ImportID: []string{"{name}"},
 		})
 
 	return &schema.Resource{
@@ -30,6 +31,7 @@ func Policy() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
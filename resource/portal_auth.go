@@ -2,11 +2,11 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
@@ -23,6 +23,7 @@ func PortalAuth() *schema.Resource {
 		UpdateContext: r.Update,
 		CreateContext: r.Create,
 		DeleteContext: r.Delete,
+		Importer:      majordomo.Importer(r.Read, nil, "{domain}"),
 
 		Schema: map[string]*schema.Schema{
 			"idp_id": {
@@ -106,9 +107,8 @@ func (r *portalAuth) Read(ctx context.Context, d *schema.ResourceData, meta any)
 
 	tgauth := tg.PortalAuth{}
 	err = tgc.Get(ctx, "/org/auth", &tgauth)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
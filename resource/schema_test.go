@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSchemas = flag.Bool("update-schemas", false, "rewrite testdata/schemas from the current resource schemas")
+
+// Probe values validators are run against, so the dump captures what a validator accepts
+// rather than which function it is.
+var (
+	stringProbes = []string{
+		"", "abc", "allow", "drop", "reject", "any", "icmp", "tcp", "udp", "accept", "forward", "dnat",
+		"auto", "manual", "full", "half", "ipsec", "vnet", "aes128-sha1", "aes128-sha256", "aes256-sha1", "aes256-sha256",
+		"onDemand", "service", "recurring", "volume", "bind", "AUDIT_CONTROL", "NET_ADMIN", "WAKE_ALARM",
+		"AUDIT_WRITE", "CHOWN", "SYS_CHROOT", "core", "nofile", "stack",
+		"10.0.0.0/8", "10.0.0.1", "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d", "edge.example.com",
+	}
+	intProbes = []int{
+		-1, 0, 1, 2, 5, 10, 14, 15, 16, 32, 100, 200, 201, 1000, 2500, 4095, 4096, 8192, 10000,
+		32768, 32769, 65535, 65536,
+	}
+)
+
+// dumpSchema writes one line per attribute, nested attributes included, with everything that
+// affects how Terraform plans and validates it.
+func dumpSchema(sm map[string]*schema.Schema) string {
+	var b strings.Builder
+	dumpAttributes(&b, "", sm)
+	return b.String()
+}
+
+func dumpAttributes(b *strings.Builder, prefix string, sm map[string]*schema.Schema) {
+	keys := make([]string, 0, len(sm))
+	for k := range sm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		dumpAttribute(b, prefix+k, sm[k])
+	}
+}
+
+func dumpAttribute(b *strings.Builder, path string, s *schema.Schema) {
+	fmt.Fprintf(b, "%s %s", path, s.Type)
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"required", s.Required},
+		{"optional", s.Optional},
+		{"computed", s.Computed},
+		{"forcenew", s.ForceNew},
+		{"sensitive", s.Sensitive},
+	} {
+		if flag.set {
+			fmt.Fprintf(b, " %s", flag.name)
+		}
+	}
+	if s.MinItems > 0 {
+		fmt.Fprintf(b, " min=%d", s.MinItems)
+	}
+	if s.MaxItems > 0 {
+		fmt.Fprintf(b, " max=%d", s.MaxItems)
+	}
+	if s.Default != nil {
+		fmt.Fprintf(b, " default=%#v", s.Default)
+	}
+	if len(s.ConflictsWith) > 0 {
+		fmt.Fprintf(b, " conflicts=%v", s.ConflictsWith)
+	}
+	if len(s.ExactlyOneOf) > 0 {
+		fmt.Fprintf(b, " exactlyoneof=%v", s.ExactlyOneOf)
+	}
+	if s.ValidateFunc != nil {
+		fmt.Fprintf(b, " validate=%v", accepted(s.Type, s.ValidateFunc))
+	}
+	fmt.Fprintf(b, " %q\n", s.Description)
+
+	switch elem := s.Elem.(type) {
+	case *schema.Resource:
+		dumpAttributes(b, path+".", elem.Schema)
+	case *schema.Schema:
+		dumpAttribute(b, path+".*", elem)
+	}
+}
+
+func accepted(t schema.ValueType, f schema.SchemaValidateFunc) []any {
+	var out []any
+	//exhaustive:ignore
+	switch t {
+	case schema.TypeString:
+		for _, p := range stringProbes {
+			if _, errs := f(p, "probe"); len(errs) == 0 {
+				out = append(out, p)
+			}
+		}
+	case schema.TypeInt:
+		for _, p := range intProbes {
+			if _, errs := f(p, "probe"); len(errs) == 0 {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// TestGeneratedSchemas checks the schemas generated from `hcl` struct tags against the ones the
+// resources used to spell out by hand.
+func TestGeneratedSchemas(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"container":      Container(),
+		"network_config": NetworkConfig(),
+	}
+
+	for name, r := range resources {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, r.InternalValidate(nil, true))
+
+			path := filepath.Join("testdata", "schemas", name+".txt")
+			got := dumpSchema(r.Schema)
+			if *updateSchemas {
+				require.NoError(t, os.WriteFile(path, []byte(got), 0o600))
+			}
+
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+		})
+	}
+}
@@ -112,8 +112,8 @@ func (r *service) Create(ctx context.Context, d *schema.ResourceData, meta any)
 
 	payload := tf.ToTG(uuid.NewString())
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(nodeOrClusterTarget(tf.NodeID, tf.ClusterFQDN))
+	defer unlock()
 
 	config, err := r.getConfig(ctx, tgc, tf)
 	if err != nil {
@@ -141,8 +141,8 @@ func (r *service) Update(ctx context.Context, d *schema.ResourceData, meta any)
 
 	payload := tf.ToTG(d.Id())
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(nodeOrClusterTarget(tf.NodeID, tf.ClusterFQDN))
+	defer unlock()
 
 	config, err := r.getConfig(ctx, tgc, tf)
 	if err != nil {
@@ -172,8 +172,8 @@ func (r *service) Delete(ctx context.Context, d *schema.ResourceData, meta any)
 		return diag.FromErr(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(nodeOrClusterTarget(tf.NodeID, tf.ClusterFQDN))
+	defer unlock()
 
 	config, err := r.getConfig(ctx, tgc, tf)
 	if err != nil {
@@ -44,6 +44,7 @@ func ServiceUser() *schema.Resource {
 			RemoteID: func(user tg.ServiceUser) string {
 				return user.Name
 			},
+			ImportID: []string{"{name}"},
 		})
 
 	return &schema.Resource{
@@ -53,6 +54,7 @@ func ServiceUser() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
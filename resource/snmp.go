@@ -2,7 +2,6 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,6 +18,7 @@ func SNMP() *schema.Resource {
 		ReadContext:   snmpRead,
 		UpdateContext: snmpUpdate,
 		DeleteContext: snmpDelete,
+		Importer:      nodeOrClusterImporter(snmpRead, nil, true),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -112,9 +112,8 @@ func snmpRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagno
 
 	n := tg.Node{}
 	err = tgc.Get(ctx, "/node/"+snmp.NodeID, &n)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -2,7 +2,6 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -25,6 +24,7 @@ func Tagging() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      nodeOrClusterImporter(r.Read, nil, false),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -138,9 +138,8 @@ func (r *tagging) Read(ctx context.Context, d *schema.ResourceData, meta any) di
 		url := "/node/" + tf.NodeID
 		tgnode := tg.Node{}
 		err := tgc.Get(ctx, url, &tgnode)
-		var nferr *tg.NotFoundError
 		switch {
-		case errors.As(err, &nferr):
+		case tg.IsNotFound(err):
 			d.SetId("")
 			return nil
 		case err != nil:
@@ -152,9 +151,8 @@ func (r *tagging) Read(ctx context.Context, d *schema.ResourceData, meta any) di
 		url := "/cluster/" + tf.ClusterFQDN
 		tgcluster := tg.Cluster{}
 		err := tgc.Get(ctx, url, &tgcluster)
-		var nferr *tg.NotFoundError
 		switch {
-		case errors.As(err, &nferr):
+		case tg.IsNotFound(err):
 			d.SetId("")
 			return nil
 		case err != nil:
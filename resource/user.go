@@ -24,6 +24,7 @@ func User() *schema.Resource {
 			RemoteID: func(user tg.User) string {
 				return user.Email
 			},
+			ImportID: []string{"{email}"},
 		})
 
 	return &schema.Resource{
@@ -73,6 +74,7 @@ func User() *schema.Resource {
 		},
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
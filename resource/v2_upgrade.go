@@ -3,7 +3,6 @@ package resource
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -24,7 +23,7 @@ func postUpgradeIdempotent(ctx context.Context, tgc *tg.Client, url string) erro
 	// The portal returns 422 with body "Cannot modify ... for V1 config" or
 	// similar when the target is already V2. Treat any 422 from the upgrade
 	// endpoint as idempotent success.
-	if strings.Contains(err.Error(), ": 422") {
+	if tg.IsUnprocessable(err) {
 		return nil
 	}
 	return err
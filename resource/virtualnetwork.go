@@ -8,12 +8,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type virtualNetwork struct {
 }
 
+// virtualNetworkDiagnostics points errors at the virtual network attributes they are about.
+var virtualNetworkDiagnostics = hcl.Diagnostics[tg.VirtualNetwork, tg.VirtualNetwork]
+
+// defaultVNetCIDR is the network_cidr default, also used when the portal doesn't report one.
+const defaultVNetCIDR = "0.0.0.0/0"
+
 func VirtualNetwork() *schema.Resource {
 	r := virtualNetwork{}
 
@@ -24,6 +31,8 @@ func VirtualNetwork() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		CustomizeDiff: r.planChange,
+		Importer:      majordomo.Importer(r.Read, nil, "{name}"),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -37,7 +46,7 @@ func VirtualNetwork() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Deprecated:   "This is only supported for legacy virtual networks",
-				Default:      "0.0.0.0/0",
+				Default:      defaultVNetCIDR,
 				ValidateFunc: validation.IsCIDR,
 			},
 			"description": {
@@ -54,42 +63,26 @@ func VirtualNetwork() *schema.Resource {
 	}
 }
 
-func vnetCommit(ctx context.Context, tgc *tg.Client, network string) error {
-	var reply struct {
-		Digest string `json:"digest"`
-	}
-
-	if err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+network+"/change/validate", &reply); err != nil {
-		return fmt.Errorf("error validating network changes: %w", err)
-	}
-
-	if _, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+network+"/change/commit", &reply); err != nil {
-		return fmt.Errorf("error committing network changes: %w", err)
-	}
-
-	return nil
-}
-
 func (vn *virtualNetwork) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
 	tf, err := hcl.DecodeResourceData[tg.VirtualNetwork](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(tg.NetworkTarget(tf.Name))
+	defer unlock()
 
 	if _, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network", &tf); err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
 	vnets := make([]tg.VirtualNetwork, 0)
 
 	err = tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network", &vnets)
 	if err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
 	for _, v := range vnets {
@@ -102,23 +95,29 @@ func (vn *virtualNetwork) Create(ctx context.Context, d *schema.ResourceData, me
 	return nil
 }
 
+// planChange records a planned update for the network's tg_virtual_network_commit, since the
+// update is staged like the changes to its routes and rules.
+func (vn *virtualNetwork) planChange(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) > 0 {
+		tg.GetClient(meta).PlanNetworkChange(d.Get("name").(string), nil) //nolint: errcheck // just trusting TF validation here
+	}
+	return nil
+}
+
 func (vn *virtualNetwork) Update(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
 	tf, err := hcl.DecodeResourceData[tg.VirtualNetwork](d)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.Name, &tf); err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, tf.Name); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, tf.Name, func() error {
+		_, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.Name, &tf)
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -129,14 +128,14 @@ func (vn *virtualNetwork) Delete(ctx context.Context, d *schema.ResourceData, me
 
 	tf, err := hcl.DecodeResourceData[tg.VirtualNetwork](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	unlock := tgc.LockTarget(tg.NetworkTarget(tf.Name))
+	defer unlock()
 
 	if err := tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.Name, &tf); err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
 	return nil
@@ -147,27 +146,29 @@ func (vn *virtualNetwork) Read(ctx context.Context, d *schema.ResourceData, meta
 
 	tf, err := hcl.DecodeResourceData[tg.VirtualNetwork](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
 	vnets := make([]tg.VirtualNetwork, 0)
 
 	err = tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network", &vnets)
 	if err != nil {
-		return diag.FromErr(err)
+		return virtualNetworkDiagnostics(err)
 	}
 
-	found := false
 	for _, v := range vnets {
 		if v.Name == tf.Name {
-			found = true
-			break
+			d.SetId(fmt.Sprintf("%d", v.ID))
+			if v.NetworkCIDR == "" {
+				v.NetworkCIDR = defaultVNetCIDR
+			}
+			if err := hcl.EncodeResourceData(&v, d); err != nil {
+				return virtualNetworkDiagnostics(err)
+			}
+			return nil
 		}
 	}
 
-	if !found {
-		d.SetId("")
-	}
-
+	d.SetId("")
 	return nil
 }
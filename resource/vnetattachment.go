@@ -2,13 +2,13 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 	"github.com/trustgrid/terraform-provider-tg/validators"
 )
@@ -33,6 +33,7 @@ func VPNAttachment() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("network"), "node:{node_id}/{network}", "cluster:{cluster_fqdn}/{network}"),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -147,9 +148,8 @@ func (vn *vpnAttachment) Read(ctx context.Context, d *schema.ResourceData, meta
 
 	vnet := tg.VPNAttachment{}
 	err = tgc.Get(ctx, tf.ResourceURL(), &vnet)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
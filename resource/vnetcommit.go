@@ -0,0 +1,253 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// networkCommitWindow is how long a commit waits for other resources to stage changes on the same
+// network, so they share it. Terraform applies a handful of resources at a time, so a large apply
+// still makes a commit for every window's worth of changes; deferred commits are the way to commit
+// once.
+const networkCommitWindow = 250 * time.Millisecond
+
+var networkCommits = tg.NewBatcher(networkCommitWindow, flushNetworkCommits)
+
+// networkCommitError is returned when the portal rejects a virtual network's staged changes.
+type networkCommitError struct {
+	Network string
+	Stage   string // Stage is "validating" or "committing".
+	Digest  string // Digest identifies the change set that was validated, if it got that far.
+	Err     error
+}
+
+func (e *networkCommitError) Error() string {
+	return fmt.Sprintf("error %s network changes: %s", e.Stage, e.Err)
+}
+
+func (e *networkCommitError) Unwrap() error {
+	return e.Err
+}
+
+// vnetCommit validates and commits every change staged on a virtual network, returning the
+// digest of the committed change set.
+func vnetCommit(ctx context.Context, tgc *tg.Client, network string) (string, error) {
+	unlock := tgc.LockTarget(tg.NetworkTarget(network))
+	defer unlock()
+
+	var reply struct {
+		Digest string `json:"digest"`
+	}
+
+	if err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+network+"/change/validate", &reply); err != nil {
+		return "", &networkCommitError{Network: network, Stage: "validating", Err: err}
+	}
+
+	if _, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+network+"/change/commit", &reply); err != nil {
+		return reply.Digest, &networkCommitError{Network: network, Stage: "committing", Digest: reply.Digest, Err: err}
+	}
+
+	tflog.Info(ctx, "committed virtual network changes", map[string]any{
+		"network": network,
+		"digest":  reply.Digest,
+	})
+
+	return reply.Digest, nil
+}
+
+// networkCommitKey is the batch a network's commits go in. Network names are only unique within
+// an org, and provider aliases can point at different orgs, so the key includes the org's domain.
+func networkCommitKey(tgc *tg.Client, network string) string {
+	return tgc.Domain + "/" + tg.NetworkTarget(network)
+}
+
+// networkCommit is a request to commit whatever is staged on a network.
+type networkCommit struct {
+	tgc     *tg.Client
+	network string
+}
+
+// flushNetworkCommits commits a network once for every resource that staged a change on it. The
+// commits in a batch share an org, so any of their clients can commit it.
+func flushNetworkCommits(ctx context.Context, _ string, commits []networkCommit) []error {
+	_, err := vnetCommit(ctx, commits[0].tgc, commits[0].network)
+
+	errs := make([]error, len(commits))
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// stageNetworkChange makes one change to a virtual network while holding its lock, then
+// commits it along with the changes other resources stage on the network at the same time.
+// With deferred commits, the change is left staged for tg_virtual_network_commit, unless that
+// resource has been destroyed and nothing is left to commit it.
+func stageNetworkChange(ctx context.Context, tgc *tg.Client, network string, change func() error) error {
+	if err := stage(tgc, network, change); err != nil {
+		return err
+	}
+
+	if tgc.DeferNetworkCommits && !tgc.NetworkCommitRetired(network) {
+		tflog.Debug(ctx, "leaving virtual network change staged", map[string]any{"network": network})
+		return nil
+	}
+
+	return networkCommits.Do(ctx, networkCommitKey(tgc, network), networkCommit{tgc: tgc, network: network})
+}
+
+// stageNetworkDeletion is stageNetworkChange for deletions, which are committed even when commits
+// are deferred: Terraform applies a tg_virtual_network_commit before it destroys the resources the
+// commit depends on, so nothing would be left to commit them. Whatever else is staged on the
+// network is committed along with the deletion.
+func stageNetworkDeletion(ctx context.Context, tgc *tg.Client, network string, change func() error) error {
+	if err := stage(tgc, network, change); err != nil {
+		return err
+	}
+
+	return networkCommits.Do(ctx, networkCommitKey(tgc, network), networkCommit{tgc: tgc, network: network})
+}
+
+// stage makes a change to a virtual network while holding its lock.
+func stage(tgc *tg.Client, network string, change func() error) error {
+	unlock := tgc.LockTarget(tg.NetworkTarget(network))
+	defer unlock()
+
+	return change()
+}
+
+// networkCommitDiags turns a staging or commit error into diagnostics, including the digest
+// of the rejected change set when there is one. Checks that fail before a change is staged
+// point at their attribute.
+func networkCommitDiags(err error) diag.Diagnostics {
+	var pathErr cty.PathError
+	if errors.As(err, &pathErr) {
+		return diag.Diagnostics{hcl.AttributeError(pathErr.Path, err.Error(), "")}
+	}
+
+	var commitErr *networkCommitError
+	if !errors.As(err, &commitErr) {
+		return diag.FromErr(err)
+	}
+
+	detail := fmt.Sprintf("The portal rejected the changes staged on virtual network %q: %s.", commitErr.Network, commitErr.Err)
+	if commitErr.Digest != "" {
+		detail += fmt.Sprintf(" Change set digest: %s.", commitErr.Digest)
+	}
+	detail += " The changes remain staged; fix the configuration and apply again, or review them in the portal."
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  commitErr.Error(),
+		Detail:   detail,
+	}}
+}
+
+type vnetCommitResource struct {
+}
+
+// VNetCommit returns a resource that validates and commits the changes staged on a virtual
+// network. It's meant for providers configured with defer_network_commits.
+func VNetCommit() *schema.Resource {
+	r := vnetCommitResource{}
+
+	return &schema.Resource{
+		Description: "Validate and commit the changes staged on a virtual network. Use with the provider's `defer_network_commits` setting so routes, rules, objects, groups and port forwards are committed once, after all of them are staged. Deletions are committed as they're staged, along with whatever else is staged on the network, since Terraform applies this resource before it destroys the resources it depends on. Make this resource depend on the staged resources: a commit is planned whenever one of them on its network plans a change, and Terraform only plans this resource after the ones it depends on. Destroying this resource makes later changes to its network commit as they're staged.",
+
+		ReadContext:   r.Read,
+		UpdateContext: r.Update,
+		DeleteContext: r.Delete,
+		CreateContext: r.Create,
+		CustomizeDiff: r.planCommit,
+
+		Schema: map[string]*schema.Schema{
+			"network": {
+				Description: "Virtual network name",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Description: "Arbitrary values that cause a new commit whenever they change",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"digest": {
+				Description: "Digest of the last committed change set",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// planCommit plans a commit when commits are deferred and a resource on the network plans a change
+// the apply will stage. Terraform plans this resource after the resources it depends on, so their
+// changes are known by then. A commit that failed, leaving no digest, is planned again.
+func (r *vnetCommitResource) planCommit(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	tgc := tg.GetClient(meta)
+	if d.Id() == "" || !tgc.DeferNetworkCommits {
+		return nil
+	}
+
+	network := d.Get("network").(string) //nolint: errcheck // just trusting TF validation here
+	if len(tgc.PlannedNetworkChanges(network)) == 0 && d.Get("digest") != "" {
+		return nil
+	}
+	return d.SetNewComputed("digest")
+}
+
+func (r *vnetCommitResource) commit(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+	network := d.Get("network").(string) //nolint: errcheck // just trusting TF validation here
+
+	digest, err := vnetCommit(ctx, tgc, network)
+	if err != nil {
+		// Without a digest, the next plan commits again.
+		if setErr := d.Set("digest", ""); setErr != nil {
+			return append(networkCommitDiags(err), diag.FromErr(setErr)...)
+		}
+		return networkCommitDiags(err)
+	}
+
+	tgc.SetNetworkCommitRetired(network, false)
+
+	d.SetId(network)
+	if err := d.Set("digest", digest); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func (r *vnetCommitResource) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	return r.commit(ctx, d, meta)
+}
+
+func (r *vnetCommitResource) Update(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	return r.commit(ctx, d, meta)
+}
+
+func (r *vnetCommitResource) Read(_ context.Context, _ *schema.ResourceData, _ any) diag.Diagnostics {
+	return nil
+}
+
+func (r *vnetCommitResource) Delete(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+	network := d.Get("network").(string) //nolint: errcheck // just trusting TF validation here
+
+	tgc.SetNetworkCommitRetired(network, true)
+	return nil
+}
@@ -0,0 +1,299 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// fakeVNetPortal keeps the routes staged on one virtual network, which it lists along with the
+// committed ones like the portal does, and counts validations and commits.
+type fakeVNetPortal struct {
+	mu     sync.Mutex
+	routes []tg.VNetRoute
+
+	staged    atomic.Int32
+	validates atomic.Int32
+	commits   atomic.Int32
+	reject    bool
+}
+
+func (p *fakeVNetPortal) client(t *testing.T, params tg.ClientParams) *tg.Client {
+	t.Helper()
+	return p.orgClient(t, "example.trustgrid.io", params)
+}
+
+// orgClient is client for an org with the given domain.
+func (p *fakeVNetPortal) orgClient(t *testing.T, domain string, params tg.ClientParams) *tg.Client {
+	t.Helper()
+
+	root := "/api/v2/domain/" + domain + "/network/vnet1"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+root+"/route", func(w http.ResponseWriter, _ *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(p.routes)
+	})
+	mux.HandleFunc("POST "+root+"/route", func(w http.ResponseWriter, r *http.Request) {
+		var route tg.VNetRoute
+		if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		route.UID = fmt.Sprintf("route-%d", p.staged.Add(1))
+		p.routes = append(p.routes, route)
+		_, _ = io.WriteString(w, `{}`)
+	})
+	mux.HandleFunc("DELETE "+root+"/route/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		p.staged.Add(1)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for i, route := range p.routes {
+			if route.UID == r.PathValue("uid") {
+				p.routes = append(p.routes[:i], p.routes[i+1:]...)
+				break
+			}
+		}
+		_, _ = io.WriteString(w, `{}`)
+	})
+	mux.HandleFunc("GET "+root+"/change/validate", func(w http.ResponseWriter, _ *http.Request) {
+		p.validates.Add(1)
+		_, _ = io.WriteString(w, `{"digest":"abc123"}`)
+	})
+	mux.HandleFunc("POST "+root+"/change/commit", func(w http.ResponseWriter, _ *http.Request) {
+		if p.reject {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = io.WriteString(w, `{"message":"route overlaps 10.0.0.0/24"}`)
+			return
+		}
+		p.commits.Add(1)
+		_, _ = io.WriteString(w, `{}`)
+	})
+
+	return newOrgTestClient(t, domain, mux, params)
+}
+
+func stageRoute(ctx context.Context, tgc *tg.Client) error {
+	return stageNetworkChange(ctx, tgc, "vnet1", func() error {
+		_, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/vnet1/route", map[string]string{})
+		return err
+	})
+}
+
+func deleteRoute(ctx context.Context, tgc *tg.Client, uid string) error {
+	return stageNetworkChange(ctx, tgc, "vnet1", func() error {
+		return tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/vnet1/route/"+uid, nil)
+	})
+}
+
+// commitResourceData is the state of a tg_virtual_network_commit for vnet1.
+func commitResourceData(t *testing.T) *schema.ResourceData {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, VNetCommit().Schema, map[string]any{"network": "vnet1"})
+	d.SetId("vnet1")
+	return d
+}
+
+func TestStageNetworkChange_OneCommitPerBatch(t *testing.T) {
+	p := &fakeVNetPortal{}
+	tgc := p.client(t, tg.ClientParams{})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, stageRoute(context.Background(), tgc))
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 5, p.staged.Load())
+	assert.EqualValues(t, 1, p.validates.Load())
+	assert.EqualValues(t, 1, p.commits.Load())
+}
+
+func TestStageNetworkChange_BatchesPerOrg(t *testing.T) {
+	acme, globex := &fakeVNetPortal{}, &fakeVNetPortal{}
+	clients := []*tg.Client{
+		acme.orgClient(t, "acme.trustgrid.io", tg.ClientParams{}),
+		globex.orgClient(t, "globex.trustgrid.io", tg.ClientParams{}),
+	}
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, stageRoute(context.Background(), clients[i%2]))
+		}()
+	}
+	wg.Wait()
+
+	for _, p := range []*fakeVNetPortal{acme, globex} {
+		assert.EqualValues(t, 2, p.staged.Load())
+		assert.EqualValues(t, 1, p.validates.Load(), "each org's vnet1 is validated")
+		assert.EqualValues(t, 1, p.commits.Load(), "each org's vnet1 is committed")
+	}
+}
+
+func TestStageNetworkChange_Deferred(t *testing.T) {
+	p := &fakeVNetPortal{}
+	tgc := p.client(t, tg.ClientParams{DeferNetworkCommits: true})
+	ctx := context.Background()
+
+	require.NoError(t, stageRoute(ctx, tgc))
+	require.NoError(t, stageRoute(ctx, tgc))
+	assert.EqualValues(t, 2, p.staged.Load())
+	assert.EqualValues(t, 0, p.commits.Load(), "changes wait for tg_virtual_network_commit")
+
+	d := commitResourceData(t)
+	require.Empty(t, VNetCommit().UpdateContext(ctx, d, tgc))
+	assert.EqualValues(t, 1, p.commits.Load(), "everything staged is committed at once")
+	assert.Equal(t, "abc123", d.Get("digest"))
+}
+
+func TestStageNetworkChange_DeferredDeletion(t *testing.T) {
+	p := &fakeVNetPortal{}
+	tgc := p.client(t, tg.ClientParams{DeferNetworkCommits: true})
+	ctx := context.Background()
+
+	require.NoError(t, stageRoute(ctx, tgc))
+	require.NoError(t, stageRoute(ctx, tgc))
+
+	// Removing a route from the config applies the commit resource, which has nothing to commit
+	// yet, before the route is destroyed.
+	r := VNetRoute()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"network": "vnet1", "uid": "route-1"})
+	d.SetId("route-1")
+	require.Empty(t, r.DeleteContext(ctx, d, tgc))
+
+	assert.EqualValues(t, 3, p.staged.Load())
+	assert.EqualValues(t, 1, p.commits.Load(), "the deletion is committed, along with what was staged before it")
+	assert.Len(t, p.routes, 1)
+}
+
+func TestStageNetworkChange_DeferredCreateFindsStagedRecord(t *testing.T) {
+	p := &fakeVNetPortal{}
+	tgc := p.client(t, tg.ClientParams{DeferNetworkCommits: true})
+	ctx := context.Background()
+
+	r := VNetRoute()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"network":      "vnet1",
+		"dest":         "edge1",
+		"network_cidr": "10.0.0.0/24",
+		"metric":       10,
+	})
+	require.Empty(t, r.CreateContext(ctx, d, tgc))
+	assert.Equal(t, "route-1", d.Id(), "the staged route is found before it's committed")
+	assert.EqualValues(t, 0, p.commits.Load())
+
+	require.Empty(t, VNetCommit().CreateContext(ctx, commitResourceData(t), tgc))
+	assert.EqualValues(t, 1, p.validates.Load())
+	assert.EqualValues(t, 1, p.commits.Load())
+}
+
+func TestStageNetworkChange_RetiredCommit(t *testing.T) {
+	p := &fakeVNetPortal{}
+	tgc := p.client(t, tg.ClientParams{DeferNetworkCommits: true})
+	ctx := context.Background()
+
+	// Dropping the commit resource from the config destroys it before the routes it depended on
+	// are changed.
+	require.Empty(t, VNetCommit().DeleteContext(ctx, commitResourceData(t), tgc))
+	require.NoError(t, stageRoute(ctx, tgc))
+	assert.EqualValues(t, 1, p.commits.Load(), "nothing is left to commit the change later")
+
+	other := p.client(t, tg.ClientParams{DeferNetworkCommits: true})
+	require.NoError(t, stageRoute(ctx, other))
+	assert.EqualValues(t, 1, p.commits.Load(), "another provider configuration still defers its changes")
+
+	require.Empty(t, VNetCommit().CreateContext(ctx, commitResourceData(t), tgc))
+	assert.EqualValues(t, 2, p.commits.Load())
+	require.NoError(t, stageRoute(ctx, tgc))
+	assert.EqualValues(t, 2, p.commits.Load(), "a new commit resource defers changes again")
+}
+
+func TestVirtualNetwork_PlansCommit(t *testing.T) {
+	tgc := (&fakeVNetPortal{}).client(t, tg.ClientParams{DeferNetworkCommits: true})
+
+	state := &terraform.InstanceState{ID: "7", Attributes: map[string]string{"id": "7", "name": "vnet1", "network_cidr": "0.0.0.0/0"}}
+	config := terraform.NewResourceConfigRaw(map[string]any{"name": "vnet1", "description": "corp"})
+	_, err := VirtualNetwork().SimpleDiff(context.Background(), state, config, tgc)
+	require.NoError(t, err)
+	assert.Len(t, tgc.PlannedNetworkChanges("vnet1"), 1, "the network's own update is staged too")
+}
+
+func TestVNetCommit_PlansCommitWhenDeferred(t *testing.T) {
+	state := &terraform.InstanceState{ID: "vnet1", Attributes: map[string]string{"id": "vnet1", "network": "vnet1", "digest": "abc123"}}
+	config := terraform.NewResourceConfigRaw(map[string]any{"network": "vnet1"})
+
+	deferred := (&fakeVNetPortal{}).client(t, tg.ClientParams{DeferNetworkCommits: true})
+	diff, err := VNetCommit().Diff(context.Background(), state, config, deferred)
+	require.NoError(t, err)
+	assert.True(t, diff == nil || diff.Empty(), "nothing on the network changes, so the plan has no changes")
+
+	deferred.PlanNetworkChange("vnet2", tg.VNetObject{Name: "db"})
+	diff, err = VNetCommit().Diff(context.Background(), state, config, deferred)
+	require.NoError(t, err)
+	assert.True(t, diff == nil || diff.Empty(), "changes to other networks don't count")
+
+	deferred.PlanNetworkChange("vnet1", tg.VNetObject{Name: "db"})
+	diff, err = VNetCommit().Diff(context.Background(), state, config, deferred)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.True(t, diff.Attributes["digest"].NewComputed)
+
+	immediate := (&fakeVNetPortal{}).client(t, tg.ClientParams{})
+	immediate.PlanNetworkChange("vnet1", tg.VNetObject{Name: "db"})
+	diff, err = VNetCommit().Diff(context.Background(), state, config, immediate)
+	require.NoError(t, err)
+	assert.True(t, diff == nil || diff.Empty(), "without deferred commits only triggers cause a commit")
+}
+
+func TestVNetCommit_RetriesFailedCommit(t *testing.T) {
+	p := &fakeVNetPortal{reject: true}
+	tgc := p.client(t, tg.ClientParams{DeferNetworkCommits: true})
+
+	d := commitResourceData(t)
+	require.NoError(t, d.Set("digest", "abc123"))
+	require.NotEmpty(t, VNetCommit().UpdateContext(context.Background(), d, tgc))
+	assert.Empty(t, d.Get("digest"))
+
+	state := &terraform.InstanceState{ID: "vnet1", Attributes: map[string]string{"id": "vnet1", "network": "vnet1", "digest": ""}}
+	config := terraform.NewResourceConfigRaw(map[string]any{"network": "vnet1"})
+	diff, err := VNetCommit().Diff(context.Background(), state, config, tgc)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.True(t, diff.Attributes["digest"].NewComputed, "the next plan commits what's still staged")
+}
+
+func TestStageNetworkChange_RejectedCommit(t *testing.T) {
+	p := &fakeVNetPortal{reject: true}
+	tgc := p.client(t, tg.ClientParams{})
+
+	err := stageRoute(context.Background(), tgc)
+	require.Error(t, err)
+	assert.True(t, tg.IsUnprocessable(err))
+
+	diags := networkCommitDiags(err)
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Summary, "error committing network changes")
+	assert.Contains(t, diags[0].Detail, "route overlaps 10.0.0.0/24")
+	assert.Contains(t, diags[0].Detail, "Change set digest: abc123")
+}
@@ -2,16 +2,21 @@ package resource
 
 import (
 	"context"
+	"slices"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type vnetGroup struct {
 }
 
+// vnetGroupDiagnostics points errors at the virtual network group attributes they are about.
+var vnetGroupDiagnostics = hcl.Diagnostics[hcl.VNetGroup, tg.VNetGroup]
+
 func VNetGroup() *schema.Resource {
 	r := vnetGroup{}
 
@@ -22,6 +27,8 @@ func VNetGroup() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		CustomizeDiff: r.planChange,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("name"), "{network}/{name}"),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -44,23 +51,39 @@ func VNetGroup() *schema.Resource {
 	}
 }
 
-func (vn *vnetGroup) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+// planChange checks that a planned group's name isn't taken.
+func (vn *vnetGroup) planChange(ctx context.Context, d *schema.ResourceDiff, meta any) error {
 	tgc := tg.GetClient(meta)
-
-	group, err := hcl.DecodeResourceData[hcl.VNetGroup](d)
+	group, err := hcl.DecodeResourceDiff[hcl.VNetGroup](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	return planVNetChange(d, tgc, group.NetworkName, group.ToTG(), func() error {
+		groups, err := vnetPlanRecords(ctx, tgc, group.NetworkName, "network-group", func(a, b tg.VNetGroup) bool { return a.Name == b.Name })
+		if err != nil {
+			return err
+		}
+		// The group is still on the network under the name it had before the plan.
+		groups = slices.DeleteFunc(groups, func(g tg.VNetGroup) bool { return g.Name == priorName(d) })
+		return checkVNetGroupConflicts(group.ToTG(), groups)
+	}, "name")
+}
 
-	if _, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+group.NetworkName+"/network-group", group.ToTG()); err != nil {
-		return diag.FromErr(err)
+func (vn *vnetGroup) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+
+	group, err := hcl.DecodeResourceData[hcl.VNetGroup](d)
+	if err != nil {
+		return vnetGroupDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, group.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, group.NetworkName, func() error {
+		_, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+group.NetworkName+"/network-group", group.ToTG())
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	d.SetId(group.Name)
@@ -73,18 +96,15 @@ func (vn *vnetGroup) Update(ctx context.Context, d *schema.ResourceData, meta an
 
 	group, err := hcl.DecodeResourceData[hcl.VNetGroup](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetGroupDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+group.NetworkName+"/network-group/"+group.Name, group.ToTG()); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, group.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, group.NetworkName, func() error {
+		_, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+group.NetworkName+"/network-group/"+group.Name, group.ToTG())
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -111,18 +131,14 @@ func (vn *vnetGroup) Delete(ctx context.Context, d *schema.ResourceData, meta an
 
 	group, err := hcl.DecodeResourceData[hcl.VNetGroup](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetGroupDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if err := tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+group.NetworkName+"/network-group/"+group.Name, group.ToTG()); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, group.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkDeletion(ctx, tgc, group.NetworkName, func() error {
+		return tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+group.NetworkName+"/network-group/"+group.Name, group.ToTG())
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -133,16 +149,20 @@ func (vn *vnetGroup) Read(ctx context.Context, d *schema.ResourceData, meta any)
 
 	tf, err := hcl.DecodeResourceData[hcl.VNetGroup](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetGroupDiagnostics(err)
 	}
 
 	group, err := vn.findGroup(ctx, tgc, tf)
-	if err != nil {
-		return diag.FromErr(err)
+	switch {
+	case tg.IsNotFound(err):
+		d.SetId("")
+		return nil
+	case err != nil:
+		return vnetGroupDiagnostics(err)
 	}
 
 	if err := hcl.EncodeResourceData(tf.UpdateFromTG(group), d); err != nil {
-		return diag.FromErr(err)
+		return vnetGroupDiagnostics(err)
 	}
 
 	return nil
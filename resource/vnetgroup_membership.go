@@ -6,12 +6,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type vnetGroupMembership struct {
 }
 
+// vnetGroupMembershipDiagnostics points errors at the virtual network group membership attributes they are about.
+var vnetGroupMembershipDiagnostics = hcl.Diagnostics[hcl.VNetGroupMembership, tg.VNetGroupMembership]
+
 func VNetGroupMembership() *schema.Resource {
 	r := vnetGroupMembership{}
 
@@ -21,6 +25,8 @@ func VNetGroupMembership() *schema.Resource {
 		ReadContext:   r.Read,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		CustomizeDiff: r.planChange,
+		Importer:      majordomo.Importer(r.Read, vnetGroupMembershipID, "{network}/{group}/{object}"),
 
 		Schema: map[string]*schema.Schema{
 			"object": {
@@ -45,30 +51,74 @@ func VNetGroupMembership() *schema.Resource {
 	}
 }
 
+// vnetGroupMembershipID returns the resource ID for a membership.
+func vnetGroupMembershipID(d *schema.ResourceData) (string, error) {
+	obj, err := hcl.DecodeResourceData[hcl.VNetGroupMembership](d)
+	if err != nil {
+		return "", err
+	}
+	return obj.Group + "-" + obj.Object, nil
+}
+
 func (vn *vnetGroupMembership) url(tgc *tg.Client, obj hcl.VNetGroupMembership) string {
 	return "/v2/domain/" + tgc.Domain + "/network/" + obj.NetworkName + "/network-group/" + obj.Group + "/" + obj.Object
 }
 
+// planChange checks that a planned membership's object and group are on the network, or planned
+// for it by the resources the membership references.
+func (vn *vnetGroupMembership) planChange(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	tgc := tg.GetClient(meta)
+	obj, err := hcl.DecodeResourceDiff[hcl.VNetGroupMembership](d)
+	if err != nil {
+		return err
+	}
+
+	return planVNetChange(d, tgc, obj.NetworkName, obj.ToTG(), func() error {
+		objects, err := vnetPlanRecords(ctx, tgc, obj.NetworkName, "network-object", func(a, b tg.VNetObject) bool { return a.Name == b.Name })
+		if err != nil {
+			return err
+		}
+		groups, err := vnetPlanRecords(ctx, tgc, obj.NetworkName, "network-group", func(a, b tg.VNetGroup) bool { return a.Name == b.Name })
+		if err != nil {
+			return err
+		}
+		return checkVNetGroupMembership(obj.ToTG(), objects, groups)
+	}, "object", "group")
+}
+
 func (vn *vnetGroupMembership) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
 	obj, err := hcl.DecodeResourceData[hcl.VNetGroupMembership](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetGroupMembershipDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	err = stageNetworkChange(ctx, tgc, obj.NetworkName, func() error {
+		objects, err := vnetRecords[tg.VNetObject](ctx, tgc, obj.NetworkName, "network-object")
+		if err != nil {
+			return err
+		}
+		groups, err := vnetRecords[tg.VNetGroup](ctx, tgc, obj.NetworkName, "network-group")
+		if err != nil {
+			return err
+		}
+		if err := checkVNetGroupMembership(obj.ToTG(), objects, groups); err != nil {
+			return err
+		}
 
-	if _, err := tgc.Post(ctx, vn.url(tgc, obj), obj.ToTG()); err != nil {
-		return diag.FromErr(err)
+		_, err = tgc.Post(ctx, vn.url(tgc, obj), obj.ToTG())
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, obj.NetworkName); err != nil {
-		return diag.FromErr(err)
+	id, err := vnetGroupMembershipID(d)
+	if err != nil {
+		return vnetGroupMembershipDiagnostics(err)
 	}
-
-	d.SetId(obj.Group + "-" + obj.Object)
+	d.SetId(id)
 
 	return nil
 }
@@ -78,18 +128,14 @@ func (vn *vnetGroupMembership) Delete(ctx context.Context, d *schema.ResourceDat
 
 	obj, err := hcl.DecodeResourceData[hcl.VNetGroupMembership](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetGroupMembershipDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if err := tgc.Delete(ctx, vn.url(tgc, obj), nil); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, obj.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkDeletion(ctx, tgc, obj.NetworkName, func() error {
+		return tgc.Delete(ctx, vn.url(tgc, obj), nil)
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -100,12 +146,17 @@ func (vn *vnetGroupMembership) Read(ctx context.Context, d *schema.ResourceData,
 
 	tf, err := hcl.DecodeResourceData[hcl.VNetGroupMembership](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetGroupMembershipDiagnostics(err)
 	}
 
 	var obj []tg.VNetGroupMembership
-	if err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/network-group/"+tf.Group, &obj); err != nil {
-		return diag.FromErr(err)
+	err = tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/network-group/"+tf.Group, &obj)
+	switch {
+	case tg.IsNotFound(err):
+		d.SetId("")
+		return nil
+	case err != nil:
+		return vnetGroupMembershipDiagnostics(err)
 	}
 
 	var membership tg.VNetGroupMembership
@@ -124,7 +175,7 @@ func (vn *vnetGroupMembership) Read(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if err := hcl.EncodeResourceData(tf.UpdateFromTG(membership), d); err != nil {
-		return diag.FromErr(err)
+		return vnetGroupMembershipDiagnostics(err)
 	}
 
 	return nil
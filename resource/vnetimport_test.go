@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func vnetImportClient(t *testing.T) *tg.Client {
+	t.Helper()
+
+	const root = "/api/v2/domain/example.trustgrid.io/network/vnet1"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/domain/example.trustgrid.io/network", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"id":7,"name":"vnet1","networkCidr":"","description":"prod","noNat":true}]`)
+	})
+	mux.HandleFunc("GET "+root+"/route", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[
+			{"uid":"r1","networkCidr":"10.0.0.0/24","nodeName":"edge1","metric":10},
+			{"uid":"r2","networkCidr":"10.0.1.0/24","nodeName":"edge1","metric":10},
+			{"uid":"r3","networkCidr":"10.0.1.0/24","nodeName":"edge1","metric":20}
+		]`)
+	})
+	mux.HandleFunc("GET "+root+"/access-policy", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"uid":"a1","action":"allow","protocol":"tcp","source":"0.0.0.0/0","dest":"public","ports":"443","lineNumber":10}]`)
+	})
+	mux.HandleFunc("GET "+root+"/network-group/web", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"objectName":"db","groupName":"web"}]`)
+	})
+	return newTestClient(t, mux, tg.ClientParams{})
+}
+
+func importState(t *testing.T, r *schema.Resource, tgc *tg.Client, id string) (*schema.ResourceData, error) {
+	t.Helper()
+
+	d := r.TestResourceData()
+	d.SetId(id)
+	states, err := r.Importer.StateContext(context.Background(), d, tgc)
+	if err != nil {
+		return nil, err
+	}
+	require.Len(t, states, 1)
+	return states[0], nil
+}
+
+func TestVNetRouteImport(t *testing.T) {
+	tgc := vnetImportClient(t)
+
+	d, err := importState(t, VNetRoute(), tgc, "vnet1/r3")
+	require.NoError(t, err)
+	assert.Equal(t, "r3", d.Id())
+	assert.Equal(t, 20, d.Get("metric"))
+
+	d, err = importState(t, VNetRoute(), tgc, "vnet1/10.0.0.0/24+edge1")
+	require.NoError(t, err)
+	assert.Equal(t, "r1", d.Id())
+	assert.Equal(t, "vnet1", d.Get("network"))
+	assert.Equal(t, "10.0.0.0/24", d.Get("network_cidr"))
+	assert.Equal(t, 10, d.Get("metric"))
+
+	_, err = importState(t, VNetRoute(), tgc, "vnet1/10.0.1.0/24+edge1")
+	assert.ErrorContains(t, err, `virtual network "vnet1" has 2 routes to 10.0.1.0/24 via edge1; import one by uid instead: r2, r3`)
+
+	_, err = importState(t, VNetRoute(), tgc, "vnet1/10.9.0.0/24+edge1")
+	assert.ErrorContains(t, err, `nothing found for import ID "vnet1/10.9.0.0/24+edge1"`)
+
+	_, err = importState(t, VNetRoute(), tgc, "vnet1/r9")
+	assert.ErrorContains(t, err, `nothing found for import ID "vnet1/r9"`)
+}
+
+func TestVNetAccessRuleImport(t *testing.T) {
+	tgc := vnetImportClient(t)
+
+	for _, id := range []string{"vnet1/10", "vnet1/a1"} {
+		d, err := importState(t, VNetAccessRule(), tgc, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, "a1", d.Id())
+		assert.Equal(t, 10, d.Get("line_number"))
+		assert.Equal(t, "443", d.Get("ports"))
+	}
+
+	_, err := importState(t, VNetAccessRule(), tgc, "vnet1/20")
+	assert.ErrorContains(t, err, `nothing found for import ID "vnet1/20"`)
+}
+
+func TestVirtualNetworkImport(t *testing.T) {
+	tgc := vnetImportClient(t)
+
+	d, err := importState(t, VirtualNetwork(), tgc, "vnet1")
+	require.NoError(t, err)
+	assert.Equal(t, "7", d.Id())
+	assert.Equal(t, "prod", d.Get("description"))
+	assert.Equal(t, true, d.Get("no_nat"))
+	assert.Equal(t, defaultVNetCIDR, d.Get("network_cidr"))
+
+	_, err = importState(t, VirtualNetwork(), tgc, "vnet2")
+	assert.ErrorContains(t, err, `nothing found for import ID "vnet2"`)
+}
+
+func TestVNetGroupMembershipImport(t *testing.T) {
+	tgc := vnetImportClient(t)
+
+	d, err := importState(t, VNetGroupMembership(), tgc, "vnet1/web/db")
+	require.NoError(t, err)
+	assert.Equal(t, "web-db", d.Id())
+	assert.Equal(t, "db", d.Get("object"))
+
+	_, err = importState(t, VNetGroupMembership(), tgc, "vnet1/web/cache")
+	assert.ErrorContains(t, err, `nothing found for import ID "vnet1/web/cache"`)
+}
@@ -2,16 +2,22 @@ package resource
 
 import (
 	"context"
+	"slices"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type vnetObject struct {
 }
 
+// vnetObjectDiagnostics points errors at the virtual network object attributes they are about.
+var vnetObjectDiagnostics = hcl.Diagnostics[hcl.VNetObject, tg.VNetObject]
+
 func VNetObject() *schema.Resource {
 	r := vnetObject{}
 
@@ -22,6 +28,8 @@ func VNetObject() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		CustomizeDiff: r.planChange,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("name"), "{network}/{name}"),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -36,31 +44,49 @@ func VNetObject() *schema.Resource {
 				ForceNew:    true,
 			},
 			"cidr": {
-				Description: "Object CIDR",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:  "Object CIDR",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsCIDR,
 			},
 		},
 	}
 }
 
-func (vn *vnetObject) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+// planChange checks that a planned object's name isn't taken and that no other object on the
+// network covers the same network.
+func (vn *vnetObject) planChange(ctx context.Context, d *schema.ResourceDiff, meta any) error {
 	tgc := tg.GetClient(meta)
-
-	obj, err := hcl.DecodeResourceData[hcl.VNetObject](d)
+	obj, err := hcl.DecodeResourceDiff[hcl.VNetObject](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	return planVNetChange(d, tgc, obj.NetworkName, obj.ToTG(), func() error {
+		objects, err := vnetPlanRecords(ctx, tgc, obj.NetworkName, "network-object", func(a, b tg.VNetObject) bool { return a.Name == b.Name })
+		if err != nil {
+			return err
+		}
+		// The object is still on the network under the name it had before the plan.
+		objects = slices.DeleteFunc(objects, func(o tg.VNetObject) bool { return o.Name == priorName(d) })
+		return checkVNetObjectConflicts(obj.ToTG(), objects)
+	}, "name", "cidr")
+}
 
-	if _, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+obj.NetworkName+"/network-object", obj.ToTG()); err != nil {
-		return diag.FromErr(err)
+func (vn *vnetObject) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+
+	obj, err := hcl.DecodeResourceData[hcl.VNetObject](d)
+	if err != nil {
+		return vnetObjectDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, obj.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, obj.NetworkName, func() error {
+		_, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+obj.NetworkName+"/network-object", obj.ToTG())
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	d.SetId(obj.Name)
@@ -73,18 +99,15 @@ func (vn *vnetObject) Update(ctx context.Context, d *schema.ResourceData, meta a
 
 	obj, err := hcl.DecodeResourceData[hcl.VNetObject](d)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+obj.NetworkName+"/network-object/"+obj.Name, obj.ToTG()); err != nil {
-		return diag.FromErr(err)
+		return vnetObjectDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, obj.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, obj.NetworkName, func() error {
+		_, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+obj.NetworkName+"/network-object/"+obj.Name, obj.ToTG())
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -95,18 +118,14 @@ func (vn *vnetObject) Delete(ctx context.Context, d *schema.ResourceData, meta a
 
 	obj, err := hcl.DecodeResourceData[hcl.VNetObject](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetObjectDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if err := tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+obj.NetworkName+"/network-object/"+obj.Name, obj.ToTG()); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, obj.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkDeletion(ctx, tgc, obj.NetworkName, func() error {
+		return tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+obj.NetworkName+"/network-object/"+obj.Name, obj.ToTG())
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -117,16 +136,21 @@ func (vn *vnetObject) Read(ctx context.Context, d *schema.ResourceData, meta any
 
 	tf, err := hcl.DecodeResourceData[hcl.VNetObject](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetObjectDiagnostics(err)
 	}
 
 	var obj tg.VNetObject
-	if err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/network-object/"+tf.Name, &obj); err != nil {
-		return diag.FromErr(err)
+	err = tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/network-object/"+tf.Name, &obj)
+	switch {
+	case tg.IsNotFound(err):
+		d.SetId("")
+		return nil
+	case err != nil:
+		return vnetObjectDiagnostics(err)
 	}
 
 	if err := hcl.EncodeResourceData(tf.UpdateFromTG(obj), d); err != nil {
-		return diag.FromErr(err)
+		return vnetObjectDiagnostics(err)
 	}
 
 	return nil
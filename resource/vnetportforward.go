@@ -3,18 +3,21 @@ package resource
 import (
 	"context"
 	"encoding/json"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type vnetPortForward struct {
 }
 
+// vnetPortForwardDiagnostics points errors at the virtual network port forward attributes they are about.
+var vnetPortForwardDiagnostics = hcl.Diagnostics[tg.VNetPortForward, tg.VNetPortForward]
+
 func VNetPortForward() *schema.Resource {
 	r := vnetPortForward{}
 
@@ -25,6 +28,8 @@ func VNetPortForward() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		CustomizeDiff: r.planChange,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("uid"), "{network}/{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -80,32 +85,45 @@ func (vn *vnetPortForward) findPortForward(ctx context.Context, tgc *tg.Client,
 	return tg.VNetPortForward{}, &tg.NotFoundError{URL: "port forward with uid " + pf.UID + " not found"}
 }
 
-func (vn *vnetPortForward) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+// planChange checks that a planned port forward's IP and port aren't already forwarded.
+func (vn *vnetPortForward) planChange(ctx context.Context, d *schema.ResourceDiff, meta any) error {
 	tgc := tg.GetClient(meta)
-
-	tf, err := hcl.DecodeResourceData[tg.VNetPortForward](d)
+	pf, err := hcl.DecodeResourceDiff[tg.VNetPortForward](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	return planVNetChange(d, tgc, pf.NetworkName, pf, func() error {
+		forwards, err := vnetPlanRecords(ctx, tgc, pf.NetworkName, "port-forwarding", func(a, b tg.VNetPortForward) bool { return sameUID(a.UID, b.UID) })
+		if err != nil {
+			return err
+		}
+		return checkVNetPortForwardConflicts(pf, forwards)
+	}, "ip", "port")
+}
 
-	reply, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/port-forwarding", &tf)
+func (vn *vnetPortForward) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+
+	tf, err := hcl.DecodeResourceData[tg.VNetPortForward](d)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	if err := json.Unmarshal(reply, &tf); err != nil {
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, tf.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, tf.NetworkName, func() error {
+		reply, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/port-forwarding", &tf)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(reply, &tf)
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	d.SetId(tf.UID)
 	if err := d.Set("uid", tf.UID); err != nil {
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
 	return nil
@@ -116,18 +134,15 @@ func (vn *vnetPortForward) Update(ctx context.Context, d *schema.ResourceData, m
 
 	tf, err := hcl.DecodeResourceData[tg.VNetPortForward](d)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/port-forwarding/"+tf.UID, &tf); err != nil {
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, tf.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, tf.NetworkName, func() error {
+		_, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/port-forwarding/"+tf.UID, &tf)
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -138,18 +153,14 @@ func (vn *vnetPortForward) Delete(ctx context.Context, d *schema.ResourceData, m
 
 	tf, err := hcl.DecodeResourceData[tg.VNetPortForward](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if err := tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/port-forwarding/"+tf.UID, &tf); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, tf.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkDeletion(ctx, tgc, tf.NetworkName, func() error {
+		return tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+tf.NetworkName+"/port-forwarding/"+tf.UID, &tf)
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -160,22 +171,21 @@ func (vn *vnetPortForward) Read(ctx context.Context, d *schema.ResourceData, met
 
 	tf, err := hcl.DecodeResourceData[tg.VNetPortForward](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
 	pf, err := vn.findPortForward(ctx, tgc, tf)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
 	pf.NetworkName = tf.NetworkName
 	if err := hcl.EncodeResourceData(pf, d); err != nil {
-		return diag.FromErr(err)
+		return vnetPortForwardDiagnostics(err)
 	}
 
 	return nil
@@ -2,19 +2,23 @@ package resource
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type vnetRoute struct {
 }
 
+// vnetRouteDiagnostics points errors at the virtual network route attributes they are about.
+var vnetRouteDiagnostics = hcl.Diagnostics[tg.VNetRoute, tg.VNetRoute]
+
 func VNetRoute() *schema.Resource {
 	r := vnetRoute{}
 
@@ -25,7 +29,8 @@ func VNetRoute() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
-		CustomizeDiff: validateVNetRouteDiff,
+		CustomizeDiff: r.planChange,
+		Importer:      majordomo.LookupImporter(r.Read, r.importLookup, "{network}/{network_cidr:[^+]+}+{dest}", "{network}/{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -119,21 +124,38 @@ func VNetRoute() *schema.Resource {
 }
 
 func validateVNetRouteDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
-	monitors, ok := d.GetOk("monitor")
-	if !ok {
-		return nil
+	if monitors, ok := d.GetOk("monitor"); ok {
+		monitorList, ok := monitors.([]any)
+		if !ok {
+			return fmt.Errorf("monitor has invalid type %T", monitors)
+		}
+
+		return validateVNetRouteMonitors(monitorList)
 	}
 
-	monitorList, ok := monitors.([]any)
-	if !ok {
-		return fmt.Errorf("monitor has invalid type %T", monitors)
+	return nil
+}
+
+// planChange checks a planned route's monitors, and that no other route on the network reaches the
+// same network with the same metric.
+func (vn *vnetRoute) planChange(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if err := validateVNetRouteDiff(ctx, d, meta); err != nil {
+		return err
 	}
 
-	if err := validateVNetRouteMonitors(monitorList); err != nil {
+	tgc := tg.GetClient(meta)
+	route, err := hcl.DecodeResourceDiff[tg.VNetRoute](d)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return planVNetChange(d, tgc, route.NetworkName, route, func() error {
+		routes, err := vnetPlanRecords(ctx, tgc, route.NetworkName, "route", func(a, b tg.VNetRoute) bool { return sameUID(a.UID, b.UID) })
+		if err != nil {
+			return err
+		}
+		return checkVNetRouteConflicts(route, routes)
+	}, "network_cidr", "dest", "metric")
 }
 
 func validateVNetRouteMonitors(monitors []any) error {
@@ -145,23 +167,23 @@ func validateVNetRouteMonitors(monitors []any) error {
 
 		enabled, ok := monitor["enabled"].(bool)
 		if !ok || !enabled {
-			return fmt.Errorf("monitor %d enabled must be true", i)
+			return hcl.PathError(fmt.Sprintf("monitor.%d.enabled", i), fmt.Errorf("monitor %d enabled must be true", i))
 		}
 
 		protocol, ok := monitor["protocol"].(string)
 		if !ok {
-			return fmt.Errorf("monitor %d protocol is required", i)
+			return hcl.PathError(fmt.Sprintf("monitor.%d.protocol", i), fmt.Errorf("monitor %d protocol is required", i))
 		}
 
 		port, _ := monitor["port"].(int)
 		switch protocol {
 		case "tcp":
 			if port < 1 {
-				return fmt.Errorf("monitor %d port is required when protocol is tcp", i)
+				return hcl.PathError(fmt.Sprintf("monitor.%d.port", i), fmt.Errorf("monitor %d port is required when protocol is tcp", i))
 			}
 		case "icmp":
 			if port > 0 {
-				return fmt.Errorf("monitor %d port must not be set when protocol is icmp", i)
+				return hcl.PathError(fmt.Sprintf("monitor.%d.port", i), fmt.Errorf("monitor %d port must not be set when protocol is icmp", i))
 			}
 		}
 	}
@@ -169,9 +191,14 @@ func validateVNetRouteMonitors(monitors []any) error {
 	return nil
 }
 
-func (vn *vnetRoute) findRoute(ctx context.Context, tgc *tg.Client, route tg.VNetRoute) (tg.VNetRoute, error) {
+func (vn *vnetRoute) listRoutes(ctx context.Context, tgc *tg.Client, network string) ([]tg.VNetRoute, error) {
 	routes := []tg.VNetRoute{}
-	err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route", &routes)
+	err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+network+"/route", &routes)
+	return routes, err
+}
+
+func (vn *vnetRoute) findRoute(ctx context.Context, tgc *tg.Client, route tg.VNetRoute) (tg.VNetRoute, error) {
+	routes, err := vn.listRoutes(ctx, tgc, route.NetworkName)
 	if err != nil {
 		return tg.VNetRoute{}, err
 	}
@@ -192,42 +219,76 @@ func (vn *vnetRoute) findRoute(ctx context.Context, tgc *tg.Client, route tg.VNe
 	return tg.VNetRoute{}, &tg.NotFoundError{URL: "route " + route.UID}
 }
 
+// importLookup resolves a route imported by network_cidr+dest to its UID. Routes imported by UID
+// are left for Read to find.
+func (vn *vnetRoute) importLookup(ctx context.Context, d *schema.ResourceData, meta any) error {
+	tgc := tg.GetClient(meta)
+
+	route, err := hcl.DecodeResourceData[tg.VNetRoute](d)
+	if err != nil {
+		return err
+	}
+
+	if route.UID == "" {
+		routes, err := vn.listRoutes(ctx, tgc, route.NetworkName)
+		if err != nil {
+			return err
+		}
+
+		var uids []string
+		for _, r := range routes {
+			if r.NetworkCIDR == route.NetworkCIDR && r.Dest == route.Dest {
+				uids = append(uids, r.UID)
+			}
+		}
+
+		switch len(uids) {
+		case 0:
+			return &tg.NotFoundError{URL: "route to " + route.NetworkCIDR + " via " + route.Dest}
+		case 1:
+			route.UID = uids[0]
+		default:
+			return fmt.Errorf("virtual network %q has %d routes to %s via %s; import one by uid instead: %s", route.NetworkName, len(uids), route.NetworkCIDR, route.Dest, strings.Join(uids, ", "))
+		}
+	}
+
+	d.SetId(route.UID)
+	return d.Set("uid", route.UID)
+}
+
 func (vn *vnetRoute) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	tgc := tg.GetClient(meta)
 
 	monitors, ok := d.Get("monitor").([]any)
 	if !ok {
-		return diag.FromErr(fmt.Errorf("monitor has invalid type %T", d.Get("monitor")))
+		return vnetRouteDiagnostics(fmt.Errorf("monitor has invalid type %T", d.Get("monitor")))
 	}
 
 	if err := validateVNetRouteMonitors(monitors); err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	route, err := hcl.DecodeResourceData[tg.VNetRoute](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route", &route); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, route.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, route.NetworkName, func() error {
+		_, err := tgc.Post(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route", &route)
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	route, err = vn.findRoute(ctx, tgc, route)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	d.SetId(route.UID)
 	if err := d.Set("uid", route.UID); err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	return nil
@@ -238,27 +299,24 @@ func (vn *vnetRoute) Update(ctx context.Context, d *schema.ResourceData, meta an
 
 	monitors, ok := d.Get("monitor").([]any)
 	if !ok {
-		return diag.FromErr(fmt.Errorf("monitor has invalid type %T", d.Get("monitor")))
+		return vnetRouteDiagnostics(fmt.Errorf("monitor has invalid type %T", d.Get("monitor")))
 	}
 
 	if err := validateVNetRouteMonitors(monitors); err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	route, err := hcl.DecodeResourceData[tg.VNetRoute](d)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route/"+route.UID, &route); err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, route.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, route.NetworkName, func() error {
+		_, err := tgc.Put(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route/"+route.UID, &route)
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -269,18 +327,14 @@ func (vn *vnetRoute) Delete(ctx context.Context, d *schema.ResourceData, meta an
 
 	route, err := hcl.DecodeResourceData[tg.VNetRoute](d)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if err := tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route/"+route.UID, &route); err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
-	if err := vnetCommit(ctx, tgc, route.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkDeletion(ctx, tgc, route.NetworkName, func() error {
+		return tgc.Delete(ctx, "/v2/domain/"+tgc.Domain+"/network/"+route.NetworkName+"/route/"+route.UID, &route)
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -291,22 +345,21 @@ func (vn *vnetRoute) Read(ctx context.Context, d *schema.ResourceData, meta any)
 
 	tf, err := hcl.DecodeResourceData[tg.VNetRoute](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	route, err := vn.findRoute(ctx, tgc, tf)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	route.NetworkName = tf.NetworkName
 	if err := hcl.EncodeResourceData(route, d); err != nil {
-		return diag.FromErr(err)
+		return vnetRouteDiagnostics(err)
 	}
 
 	return nil
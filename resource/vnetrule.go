@@ -2,18 +2,22 @@ package resource
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 )
 
 type vnetAccessRule struct {
 }
 
+// vnetRuleDiagnostics points errors at the virtual network access rule attributes they are about.
+var vnetRuleDiagnostics = hcl.Diagnostics[tg.VNetAccessRule, tg.VNetAccessRule]
+
 func VNetAccessRule() *schema.Resource {
 	r := vnetAccessRule{}
 
@@ -24,6 +28,11 @@ func VNetAccessRule() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		CustomizeDiff: r.planChange,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateVNetAccessRuleConfig,
+		},
+		Importer: majordomo.LookupImporter(r.Read, r.importLookup, "{network}/{line_number:[0-9]+}", "{network}/{uid}"),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -87,9 +96,15 @@ func (vn *vnetAccessRule) ruleURL(tgc *tg.Client, rule tg.VNetAccessRule) string
 	return vn.urlRoot(tgc, rule) + "/" + rule.UID
 }
 
-func (vn *vnetAccessRule) findRule(ctx context.Context, tgc *tg.Client, rule tg.VNetAccessRule) (tg.VNetAccessRule, error) {
+func (vn *vnetAccessRule) listRules(ctx context.Context, tgc *tg.Client, rule tg.VNetAccessRule) ([]tg.VNetAccessRule, error) {
 	rules := []tg.VNetAccessRule{}
-	if err := tgc.Get(ctx, vn.urlRoot(tgc, rule), &rules); err != nil {
+	err := tgc.Get(ctx, vn.urlRoot(tgc, rule), &rules)
+	return rules, err
+}
+
+func (vn *vnetAccessRule) findRule(ctx context.Context, tgc *tg.Client, rule tg.VNetAccessRule) (tg.VNetAccessRule, error) {
+	rules, err := vn.listRules(ctx, tgc, rule)
+	if err != nil {
 		return tg.VNetAccessRule{}, err
 	}
 
@@ -107,36 +122,81 @@ func (vn *vnetAccessRule) findRule(ctx context.Context, tgc *tg.Client, rule tg.
 		}
 	}
 
-	return tg.VNetAccessRule{}, nil
+	return tg.VNetAccessRule{}, &tg.NotFoundError{URL: "access rule " + rule.UID}
 }
 
-func (vn *vnetAccessRule) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+// planChange checks that a planned rule's line number isn't taken by another rule on the network.
+func (vn *vnetAccessRule) planChange(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	tgc := tg.GetClient(meta)
+	rule, err := hcl.DecodeResourceDiff[tg.VNetAccessRule](d)
+	if err != nil {
+		return err
+	}
+
+	return planVNetChange(d, tgc, rule.NetworkName, rule, func() error {
+		rules, err := vnetPlanRecords(ctx, tgc, rule.NetworkName, "access-policy", func(a, b tg.VNetAccessRule) bool { return sameUID(a.UID, b.UID) })
+		if err != nil {
+			return err
+		}
+		return checkVNetAccessRuleConflicts(rule, rules)
+	}, "line_number")
+}
+
+// importLookup resolves a rule imported by line number to its UID. Rules imported by UID are
+// left for Read to find.
+func (vn *vnetAccessRule) importLookup(ctx context.Context, d *schema.ResourceData, meta any) error {
 	tgc := tg.GetClient(meta)
 
 	rule, err := hcl.DecodeResourceData[tg.VNetAccessRule](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
+	if rule.UID == "" {
+		rules, err := vn.listRules(ctx, tgc, rule)
+		if err != nil {
+			return err
+		}
 
-	if _, err := tgc.Post(ctx, vn.urlRoot(tgc, rule), &rule); err != nil {
-		return diag.FromErr(err)
+		for _, r := range rules {
+			if r.LineNumber == rule.LineNumber {
+				rule.UID = r.UID
+				break
+			}
+		}
+		if rule.UID == "" {
+			return &tg.NotFoundError{URL: fmt.Sprintf("access rule on line %d", rule.LineNumber)}
+		}
 	}
 
-	if err := vnetCommit(ctx, tgc, rule.NetworkName); err != nil {
-		return diag.FromErr(err)
+	d.SetId(rule.UID)
+	return d.Set("uid", rule.UID)
+}
+
+func (vn *vnetAccessRule) Create(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	tgc := tg.GetClient(meta)
+
+	rule, err := hcl.DecodeResourceData[tg.VNetAccessRule](d)
+	if err != nil {
+		return vnetRuleDiagnostics(err)
+	}
+
+	err = stageNetworkChange(ctx, tgc, rule.NetworkName, func() error {
+		_, err := tgc.Post(ctx, vn.urlRoot(tgc, rule), &rule)
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	rule, err = vn.findRule(ctx, tgc, rule)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 
 	d.SetId(rule.UID)
 	if err := d.Set("uid", rule.UID); err != nil {
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 
 	return nil
@@ -147,18 +207,15 @@ func (vn *vnetAccessRule) Update(ctx context.Context, d *schema.ResourceData, me
 
 	rule, err := hcl.DecodeResourceData[tg.VNetAccessRule](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if _, err := tgc.Put(ctx, vn.ruleURL(tgc, rule), &rule); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, rule.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkChange(ctx, tgc, rule.NetworkName, func() error {
+		_, err := tgc.Put(ctx, vn.ruleURL(tgc, rule), &rule)
+		return err
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -169,18 +226,14 @@ func (vn *vnetAccessRule) Delete(ctx context.Context, d *schema.ResourceData, me
 
 	rule, err := hcl.DecodeResourceData[tg.VNetAccessRule](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 
-	tgc.Lock.Lock()
-	defer tgc.Lock.Unlock()
-
-	if err := tgc.Delete(ctx, vn.ruleURL(tgc, rule), &rule); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := vnetCommit(ctx, tgc, rule.NetworkName); err != nil {
-		return diag.FromErr(err)
+	err = stageNetworkDeletion(ctx, tgc, rule.NetworkName, func() error {
+		return tgc.Delete(ctx, vn.ruleURL(tgc, rule), &rule)
+	})
+	if err != nil {
+		return networkCommitDiags(err)
 	}
 
 	return nil
@@ -191,22 +244,21 @@ func (vn *vnetAccessRule) Read(ctx context.Context, d *schema.ResourceData, meta
 
 	tf, err := hcl.DecodeResourceData[tg.VNetAccessRule](d)
 	if err != nil {
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 
 	rule, err := vn.findRule(ctx, tgc, tf)
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 
 	rule.NetworkName = tf.NetworkName
 	if err := hcl.EncodeResourceData(rule, d); err != nil {
-		return diag.FromErr(err)
+		return vnetRuleDiagnostics(err)
 	}
 	return nil
 }
@@ -0,0 +1,261 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+// The portal only validates changes once they're staged, so these checks run the parts of its
+// validation that can be decided locally, while the change is planned.
+//
+// Each vnet child resource checks the record its plan creates or changes against the records
+// already on the network and against the new ones other resources planned earlier in the run. A
+// resource is planned after the resources it references, so a group membership sees the object
+// and group it names even when they're created in the same apply. Terraform doesn't tell the
+// provider what a plan deletes, though, so a record the plan removes still counts: moving a route,
+// rule or port forward onto a record that's being deleted takes two applies.
+
+// vnetRecords lists a virtual network's records of the given kind.
+func vnetRecords[T any](ctx context.Context, tgc *tg.Client, network string, kind string) ([]T, error) {
+	var records []T
+	if err := tgc.Get(ctx, "/v2/domain/"+tgc.Domain+"/network/"+network+"/"+kind, &records); err != nil {
+		return nil, fmt.Errorf("error checking %s on virtual network %q: %w", kind, network, err)
+	}
+	return records, nil
+}
+
+// vnetPlanRecords lists a virtual network's records of the given kind along with the new ones other
+// resources have planned in this run. same reports whether a planned record is one the network
+// already has, which the plan only changes. A network that doesn't exist yet has no records.
+func vnetPlanRecords[T any](ctx context.Context, tgc *tg.Client, network string, kind string, same func(a, b T) bool) ([]T, error) {
+	records, err := vnetRecords[T](ctx, tgc, network, kind)
+	if err != nil && !tg.IsNotFound(err) {
+		return nil, err
+	}
+
+	existing := len(records)
+	for _, change := range tgc.PlannedNetworkChanges(network) {
+		planned, ok := change.(T)
+		if !ok || slices.ContainsFunc(records[:existing], func(r T) bool { return same(r, planned) }) {
+			continue
+		}
+		records = append(records, planned)
+	}
+	return records, nil
+}
+
+// planVNetChange runs check when a plan creates or changes a virtual network record, then records
+// the change so the plans of other resources in the run can see it. The check is skipped while any
+// of attrs, the values it looks at, won't be known until apply.
+func planVNetChange(d *schema.ResourceDiff, tgc *tg.Client, network string, record any, check func() error, attrs ...string) error {
+	if (d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0) || !d.NewValueKnown("network") {
+		return nil
+	}
+
+	for _, attr := range attrs {
+		if !d.NewValueKnown(attr) {
+			tgc.PlanNetworkChange(network, nil)
+			return nil
+		}
+	}
+
+	if err := check(); err != nil {
+		return err
+	}
+	tgc.PlanNetworkChange(network, record)
+	return nil
+}
+
+// priorName is the name a named record, like an object or group, had on the network before the plan.
+// A record moving to another network is new there.
+func priorName(d *schema.ResourceDiff) string {
+	if d.HasChange("network") {
+		return ""
+	}
+	return d.Id()
+}
+
+// sameUID reports whether two records are the same record on the portal. New records don't have
+// a UID yet, so they're never the same as any other.
+func sameUID(a, b string) bool {
+	return a != "" && a == b
+}
+
+// sameNetwork reports whether two CIDRs are the same network once their host bits are masked,
+// like 10.0.0.5/24 and 10.0.0.0/24. CIDRs that don't parse are compared as written.
+func sameNetwork(a, b string) bool {
+	_, an, aErr := net.ParseCIDR(a)
+	_, bn, bErr := net.ParseCIDR(b)
+	if aErr != nil || bErr != nil {
+		return a == b
+	}
+	return an.String() == bn.String()
+}
+
+// checkVNetRouteConflicts rejects a route to a network another route already reaches with the same
+// metric. Through the same dest the route is a duplicate the provider can't tell apart from it,
+// and through another the portal has no way to pick between them.
+func checkVNetRouteConflicts(route tg.VNetRoute, existing []tg.VNetRoute) error {
+	for _, r := range existing {
+		if sameUID(r.UID, route.UID) || r.Metric != route.Metric || !sameNetwork(r.NetworkCIDR, route.NetworkCIDR) {
+			continue
+		}
+		if r.Dest == route.Dest {
+			return hcl.PathError("network_cidr", fmt.Errorf("virtual network %q already has a route to %s via %s with metric %d%s; import it instead", route.NetworkName, r.NetworkCIDR, r.Dest, r.Metric, uidNote(r.UID)))
+		}
+		return hcl.PathError("metric", fmt.Errorf("virtual network %q also routes %s via %s with metric %d%s; give one of them a different metric", route.NetworkName, r.NetworkCIDR, r.Dest, r.Metric, uidNote(r.UID)))
+	}
+	return nil
+}
+
+// uidNote names an existing record's UID in an error, or nothing for a record that's only planned.
+func uidNote(uid string) string {
+	if uid == "" {
+		return " planned in this run"
+	}
+	return " (uid " + uid + ")"
+}
+
+// checkVNetAccessRule checks the rule's destination and ports against its protocol.
+func checkVNetAccessRule(rule tg.VNetAccessRule) error {
+	if rule.Dest != "" && rule.Dest != "public" && rule.Dest != "private" {
+		if _, _, err := net.ParseCIDR(rule.Dest); err != nil {
+			return hcl.PathError("dest", fmt.Errorf("dest %q must be a CIDR, \"public\" or \"private\"", rule.Dest))
+		}
+	}
+
+	if rule.Ports == "" {
+		return nil
+	}
+	if rule.Protocol != "tcp" && rule.Protocol != "udp" {
+		return hcl.PathError("ports", fmt.Errorf("ports can only be set when protocol is tcp or udp, not %q", rule.Protocol))
+	}
+	for _, part := range strings.Split(rule.Ports, ",") {
+		if err := checkPortRange(strings.TrimSpace(part)); err != nil {
+			return hcl.PathError("ports", fmt.Errorf("ports %q: %w", rule.Ports, err))
+		}
+	}
+	return nil
+}
+
+// checkPortRange checks a single port like 22 or a range like 80-1024.
+func checkPortRange(s string) error {
+	lo, hi, isRange := strings.Cut(s, "-")
+	from, err := strconv.Atoi(lo)
+	if err != nil || from < 1 || from > 65535 {
+		return fmt.Errorf("%q is not a port between 1 and 65535", lo)
+	}
+	if !isRange {
+		return nil
+	}
+	to, err := strconv.Atoi(hi)
+	if err != nil || to < 1 || to > 65535 {
+		return fmt.Errorf("%q is not a port between 1 and 65535", hi)
+	}
+	if from > to {
+		return fmt.Errorf("range %s starts after it ends", s)
+	}
+	return nil
+}
+
+// validateVNetAccessRuleConfig rejects rules checkVNetAccessRule finds fault with.
+func validateVNetAccessRuleConfig(_ context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	rule := tg.VNetAccessRule{
+		Protocol: rawString(req.RawConfig, "protocol"),
+		Dest:     rawString(req.RawConfig, "dest"),
+		Ports:    rawString(req.RawConfig, "ports"),
+	}
+	if rule.Protocol == "" {
+		// The protocol isn't known yet, so only the ports themselves can be checked.
+		rule.Protocol = "tcp"
+	}
+
+	if err := checkVNetAccessRule(rule); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, vnetRuleDiagnostics(err)...)
+	}
+}
+
+// rawString returns a string attribute of a raw config, or "" when it's null or not known yet.
+func rawString(config cty.Value, attr string) string {
+	if !config.IsKnown() || config.IsNull() || !config.Type().HasAttribute(attr) {
+		return ""
+	}
+	v := config.GetAttr(attr)
+	if !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+		return ""
+	}
+	return v.AsString()
+}
+
+// checkVNetAccessRuleConflicts rejects a rule on a line number that's already taken.
+func checkVNetAccessRuleConflicts(rule tg.VNetAccessRule, existing []tg.VNetAccessRule) error {
+	for _, r := range existing {
+		if !sameUID(r.UID, rule.UID) && r.LineNumber == rule.LineNumber {
+			return hcl.PathError("line_number", fmt.Errorf("line_number %d is already used by access rule%s on virtual network %q", rule.LineNumber, uidNote(r.UID), rule.NetworkName))
+		}
+	}
+	return nil
+}
+
+// checkVNetPortForwardConflicts rejects a port forward for an IP and port that's already forwarded.
+func checkVNetPortForwardConflicts(pf tg.VNetPortForward, existing []tg.VNetPortForward) error {
+	for _, r := range existing {
+		if !sameUID(r.UID, pf.UID) && r.IP == pf.IP && r.Port == pf.Port {
+			return hcl.PathError("port", fmt.Errorf("%s:%d is already forwarded to %s on %s%s", pf.IP, pf.Port, r.Service, r.Node, uidNote(r.UID)))
+		}
+	}
+	return nil
+}
+
+// checkVNetObjectConflicts rejects an object whose name is taken, or an object for a network
+// another object already covers. existing leaves out the object itself.
+func checkVNetObjectConflicts(obj tg.VNetObject, existing []tg.VNetObject) error {
+	for _, o := range existing {
+		switch {
+		case o.Name == obj.Name:
+			return hcl.PathError("name", fmt.Errorf("network object %q already exists on the virtual network; import it instead", obj.Name))
+		case sameNetwork(o.CIDR, obj.CIDR):
+			return hcl.PathError("cidr", fmt.Errorf("network object %q already covers %s", o.Name, o.CIDR))
+		}
+	}
+	return nil
+}
+
+// checkVNetGroupConflicts rejects a group whose name is taken. existing leaves out the group itself.
+func checkVNetGroupConflicts(group tg.VNetGroup, existing []tg.VNetGroup) error {
+	for _, g := range existing {
+		if g.Name == group.Name {
+			return hcl.PathError("name", fmt.Errorf("network group %q already exists on the virtual network; import it instead", group.Name))
+		}
+	}
+	return nil
+}
+
+// checkVNetGroupMembership rejects a membership whose object or group isn't on the network.
+func checkVNetGroupMembership(m tg.VNetGroupMembership, objects []tg.VNetObject, groups []tg.VNetGroup) error {
+	if !containsName(objects, m.Object, func(o tg.VNetObject) string { return o.Name }) {
+		return hcl.PathError("object", fmt.Errorf("network object %q doesn't exist on the virtual network", m.Object))
+	}
+	if !containsName(groups, m.Group, func(g tg.VNetGroup) string { return g.Name }) {
+		return hcl.PathError("group", fmt.Errorf("network group %q doesn't exist on the virtual network", m.Group))
+	}
+	return nil
+}
+
+func containsName[T any](records []T, name string, nameOf func(T) string) bool {
+	for _, r := range records {
+		if nameOf(r) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,343 @@
+package resource
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/tg"
+)
+
+func TestCheckVNetAccessRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule tg.VNetAccessRule
+		err  string
+		path string
+	}{
+		{
+			name: "cidr dest with port range",
+			rule: tg.VNetAccessRule{Protocol: "tcp", Dest: "10.0.0.0/24", Ports: "80-1024"},
+		},
+		{
+			name: "public dest with port list",
+			rule: tg.VNetAccessRule{Protocol: "udp", Dest: "public", Ports: "53, 123"},
+		},
+		{
+			name: "any protocol without ports",
+			rule: tg.VNetAccessRule{Protocol: "any", Dest: "private"},
+		},
+		{
+			name: "bad dest",
+			rule: tg.VNetAccessRule{Protocol: "any", Dest: "somewhere"},
+			err:  `dest "somewhere" must be a CIDR, "public" or "private"`,
+			path: "dest",
+		},
+		{
+			name: "ports on icmp",
+			rule: tg.VNetAccessRule{Protocol: "icmp", Dest: "public", Ports: "22"},
+			err:  `ports can only be set when protocol is tcp or udp, not "icmp"`,
+			path: "ports",
+		},
+		{
+			name: "port out of range",
+			rule: tg.VNetAccessRule{Protocol: "tcp", Dest: "public", Ports: "70000"},
+			err:  `ports "70000": "70000" is not a port between 1 and 65535`,
+			path: "ports",
+		},
+		{
+			name: "backwards range",
+			rule: tg.VNetAccessRule{Protocol: "tcp", Dest: "public", Ports: "1024-80"},
+			err:  `ports "1024-80": range 1024-80 starts after it ends`,
+			path: "ports",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVNetAccessRule(tt.rule)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+
+			var pathErr cty.PathError
+			require.ErrorAs(t, err, &pathErr)
+			assert.Equal(t, hcl.AttributePath(tt.path), pathErr.Path)
+		})
+	}
+}
+
+func TestValidateVNetAccessRuleConfig(t *testing.T) {
+	validate := func(config map[string]cty.Value) diag.Diagnostics {
+		var resp schema.ValidateResourceConfigFuncResponse
+		validateVNetAccessRuleConfig(context.Background(), schema.ValidateResourceConfigFuncRequest{RawConfig: cty.ObjectVal(config)}, &resp)
+		return resp.Diagnostics
+	}
+
+	assert.Empty(t, validate(map[string]cty.Value{
+		"protocol": cty.StringVal("tcp"),
+		"dest":     cty.StringVal("10.0.0.0/24"),
+		"ports":    cty.StringVal("22"),
+	}))
+	assert.Empty(t, validate(map[string]cty.Value{
+		"protocol": cty.UnknownVal(cty.String),
+		"dest":     cty.UnknownVal(cty.String),
+		"ports":    cty.NullVal(cty.String),
+	}))
+
+	diags := validate(map[string]cty.Value{
+		"protocol": cty.StringVal("icmp"),
+		"dest":     cty.StringVal("public"),
+		"ports":    cty.StringVal("22"),
+	})
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Error, diags[0].Severity)
+	assert.Equal(t, hcl.AttributePath("ports"), diags[0].AttributePath)
+
+	diags = validate(map[string]cty.Value{
+		"protocol": cty.UnknownVal(cty.String),
+		"dest":     cty.StringVal("somewhere"),
+		"ports":    cty.NullVal(cty.String),
+	})
+	require.Len(t, diags, 1)
+	assert.Equal(t, hcl.AttributePath("dest"), diags[0].AttributePath)
+}
+
+func TestCheckVNetRouteConflicts(t *testing.T) {
+	existing := []tg.VNetRoute{
+		{UID: "r1", NetworkCIDR: "10.0.0.0/24", Dest: "edge1", Metric: 10},
+	}
+
+	assert.NoError(t, checkVNetRouteConflicts(tg.VNetRoute{NetworkCIDR: "10.0.0.0/24", Dest: "edge2", Metric: 20}, existing))
+	assert.NoError(t, checkVNetRouteConflicts(tg.VNetRoute{NetworkCIDR: "10.0.0.0/16", Dest: "edge2", Metric: 10}, existing), "more specific routes win")
+	assert.NoError(t, checkVNetRouteConflicts(tg.VNetRoute{UID: "r1", NetworkCIDR: "10.0.0.0/24", Dest: "edge1", Metric: 10}, existing))
+
+	err := checkVNetRouteConflicts(tg.VNetRoute{NetworkName: "vnet1", NetworkCIDR: "10.0.0.9/24", Dest: "edge1", Metric: 10}, existing)
+	assert.EqualError(t, err, `virtual network "vnet1" already has a route to 10.0.0.0/24 via edge1 with metric 10 (uid r1); import it instead`)
+	var pathErr cty.PathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("network_cidr"), pathErr.Path)
+
+	err = checkVNetRouteConflicts(tg.VNetRoute{NetworkName: "vnet1", NetworkCIDR: "10.0.0.0/24", Dest: "edge2", Metric: 10}, existing)
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("metric"), pathErr.Path)
+
+	planned := []tg.VNetRoute{{NetworkCIDR: "10.0.0.0/24", Dest: "edge1", Metric: 10}}
+	err = checkVNetRouteConflicts(tg.VNetRoute{NetworkName: "vnet1", NetworkCIDR: "10.0.0.0/24", Dest: "edge2", Metric: 10}, planned)
+	assert.EqualError(t, err, `virtual network "vnet1" also routes 10.0.0.0/24 via edge1 with metric 10 planned in this run; give one of them a different metric`, "new routes conflict with each other")
+}
+
+func TestCheckVNetAccessRuleConflicts(t *testing.T) {
+	existing := []tg.VNetAccessRule{{UID: "a1", LineNumber: 10}}
+
+	assert.NoError(t, checkVNetAccessRuleConflicts(tg.VNetAccessRule{LineNumber: 20}, existing))
+	assert.NoError(t, checkVNetAccessRuleConflicts(tg.VNetAccessRule{UID: "a1", LineNumber: 10}, existing))
+
+	err := checkVNetAccessRuleConflicts(tg.VNetAccessRule{NetworkName: "vnet1", LineNumber: 10}, existing)
+	assert.EqualError(t, err, `line_number 10 is already used by access rule (uid a1) on virtual network "vnet1"`)
+	var pathErr cty.PathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("line_number"), pathErr.Path)
+}
+
+func TestCheckVNetPortForwardConflicts(t *testing.T) {
+	existing := []tg.VNetPortForward{{UID: "p1", Node: "edge1", Service: "ssh", IP: "10.0.0.5", Port: 22}}
+
+	assert.NoError(t, checkVNetPortForwardConflicts(tg.VNetPortForward{IP: "10.0.0.5", Port: 2222}, existing))
+	assert.NoError(t, checkVNetPortForwardConflicts(tg.VNetPortForward{UID: "p1", IP: "10.0.0.5", Port: 22}, existing))
+
+	err := checkVNetPortForwardConflicts(tg.VNetPortForward{IP: "10.0.0.5", Port: 22}, existing)
+	assert.EqualError(t, err, "10.0.0.5:22 is already forwarded to ssh on edge1 (uid p1)")
+}
+
+func TestCheckVNetObjectConflicts(t *testing.T) {
+	existing := []tg.VNetObject{{Name: "db", CIDR: "10.0.1.0/24"}}
+
+	assert.NoError(t, checkVNetObjectConflicts(tg.VNetObject{Name: "web", CIDR: "10.0.2.0/24"}, existing))
+
+	err := checkVNetObjectConflicts(tg.VNetObject{Name: "db", CIDR: "10.0.3.0/24"}, existing)
+	assert.EqualError(t, err, `network object "db" already exists on the virtual network; import it instead`)
+
+	err = checkVNetObjectConflicts(tg.VNetObject{Name: "db-alias", CIDR: "10.0.1.7/24"}, existing)
+	assert.EqualError(t, err, `network object "db" already covers 10.0.1.0/24`)
+	var pathErr cty.PathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("cidr"), pathErr.Path)
+}
+
+func TestCheckVNetGroupConflicts(t *testing.T) {
+	existing := []tg.VNetGroup{{Name: "servers"}}
+
+	assert.NoError(t, checkVNetGroupConflicts(tg.VNetGroup{Name: "clients"}, existing))
+	assert.Error(t, checkVNetGroupConflicts(tg.VNetGroup{Name: "servers"}, existing))
+}
+
+func TestCheckVNetGroupMembership(t *testing.T) {
+	objects := []tg.VNetObject{{Name: "db", CIDR: "10.0.1.0/24"}}
+	groups := []tg.VNetGroup{{Name: "servers"}}
+
+	assert.NoError(t, checkVNetGroupMembership(tg.VNetGroupMembership{Object: "db", Group: "servers"}, objects, groups))
+
+	err := checkVNetGroupMembership(tg.VNetGroupMembership{Object: "web", Group: "servers"}, objects, groups)
+	assert.EqualError(t, err, `network object "web" doesn't exist on the virtual network`)
+	var pathErr cty.PathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("object"), pathErr.Path)
+
+	err = checkVNetGroupMembership(tg.VNetGroupMembership{Object: "db", Group: "clients"}, objects, groups)
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("group"), pathErr.Path)
+}
+
+func TestVNetGroupMembership_MissingObjectIsNotStaged(t *testing.T) {
+	const root = "/api/v2/domain/example.trustgrid.io/network/vnet1"
+
+	var staged bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+root+"/network-object", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"name":"db","cidr":"10.0.1.0/24"}]`)
+	})
+	mux.HandleFunc("GET "+root+"/network-group", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"name":"servers"}]`)
+	})
+	mux.HandleFunc("POST "+root+"/network-group/{group}/{object}", func(w http.ResponseWriter, _ *http.Request) {
+		staged = true
+		_, _ = io.WriteString(w, `{}`)
+	})
+	tgc := newTestClient(t, mux, tg.ClientParams{})
+
+	r := VNetGroupMembership()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{"network": "vnet1", "group": "servers", "object": "web"})
+
+	diags := r.CreateContext(context.Background(), d, tgc)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Error, diags[0].Severity)
+	assert.Equal(t, hcl.AttributePath("object"), diags[0].AttributePath)
+	assert.False(t, staged)
+}
+
+func TestVNetRoute_PlanRejectsOverlaps(t *testing.T) {
+	p := &fakeVNetPortal{routes: []tg.VNetRoute{{UID: "route-0", NetworkCIDR: "10.0.0.0/24", Dest: "edge1", Metric: 10}}}
+	tgc := p.client(t, tg.ClientParams{})
+	ctx := context.Background()
+
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"network":      "vnet1",
+		"dest":         "edge2",
+		"network_cidr": "10.0.0.0/24",
+		"metric":       10,
+	})
+	_, err := VNetRoute().SimpleDiff(ctx, nil, config, tgc)
+	assert.EqualError(t, err, `virtual network "vnet1" also routes 10.0.0.0/24 via edge1 with metric 10 (uid route-0); give one of them a different metric`)
+	var pathErr cty.PathError
+	require.ErrorAs(t, err, &pathErr, "the SDK only points at the attribute when the error is returned unwrapped")
+	assert.Equal(t, hcl.AttributePath("metric"), pathErr.Path)
+
+	// The route already on the network is left out of its own check.
+	state := &terraform.InstanceState{ID: "route-0", Attributes: map[string]string{
+		"id":           "route-0",
+		"uid":          "route-0",
+		"network":      "vnet1",
+		"dest":         "edge1",
+		"network_cidr": "10.0.0.0/24",
+		"metric":       "10",
+	}}
+	config = terraform.NewResourceConfigRaw(map[string]any{
+		"network":      "vnet1",
+		"dest":         "edge1",
+		"network_cidr": "10.0.0.0/24",
+		"metric":       10,
+		"description":  "primary",
+	})
+	_, err = VNetRoute().SimpleDiff(ctx, state, config, tgc)
+	assert.NoError(t, err)
+	assert.Len(t, tgc.PlannedNetworkChanges("vnet1"), 1, "the update is planned for the network")
+}
+
+func TestVNetRoute_PlanSkipsUnchangedRoutes(t *testing.T) {
+	var gets atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/domain/example.trustgrid.io/network/vnet1/route", func(w http.ResponseWriter, _ *http.Request) {
+		gets.Add(1)
+		_, _ = io.WriteString(w, `[]`)
+	})
+	tgc := newTestClient(t, mux, tg.ClientParams{})
+
+	state := &terraform.InstanceState{ID: "route-0", Attributes: map[string]string{
+		"id":           "route-0",
+		"uid":          "route-0",
+		"network":      "vnet1",
+		"dest":         "edge1",
+		"network_cidr": "10.0.0.0/24",
+		"metric":       "10",
+	}}
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"network":      "vnet1",
+		"dest":         "edge1",
+		"network_cidr": "10.0.0.0/24",
+		"metric":       10,
+	})
+	_, err := VNetRoute().SimpleDiff(context.Background(), state, config, tgc)
+	require.NoError(t, err)
+	assert.Zero(t, gets.Load(), "routes the plan doesn't change aren't checked")
+	assert.Empty(t, tgc.PlannedNetworkChanges("vnet1"))
+}
+
+func TestVNetGroupMembership_PlanSeesPlannedObjects(t *testing.T) {
+	const root = "/api/v2/domain/example.trustgrid.io/network/vnet1"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+root+"/network-object", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"name":"db","cidr":"10.0.1.0/24"}]`)
+	})
+	mux.HandleFunc("GET "+root+"/network-group", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `[{"name":"servers"}]`)
+	})
+	tgc := newTestClient(t, mux, tg.ClientParams{})
+	ctx := context.Background()
+
+	membership := func(object string) error {
+		config := terraform.NewResourceConfigRaw(map[string]any{"network": "vnet1", "group": "servers", "object": object})
+		_, err := VNetGroupMembership().SimpleDiff(ctx, nil, config, tgc)
+		return err
+	}
+
+	assert.NoError(t, membership("db"))
+
+	err := membership("web")
+	assert.EqualError(t, err, `network object "web" doesn't exist on the virtual network`)
+	var pathErr cty.PathError
+	require.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, hcl.AttributePath("object"), pathErr.Path)
+
+	// Terraform plans the object a membership references before the membership.
+	_, err = VNetObject().SimpleDiff(ctx, nil, terraform.NewResourceConfigRaw(map[string]any{"network": "vnet1", "name": "web", "cidr": "10.0.2.0/24"}), tgc)
+	require.NoError(t, err)
+	assert.NoError(t, membership("web"))
+
+	_, err = VNetObject().SimpleDiff(ctx, nil, terraform.NewResourceConfigRaw(map[string]any{"network": "vnet1", "name": "web-alias", "cidr": "10.0.2.0/24"}), tgc)
+	assert.EqualError(t, err, `network object "web" already covers 10.0.2.0/24`, "objects planned in the same run conflict too")
+}
+
+func TestVNetObject_PlanOnNewNetwork(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/domain/example.trustgrid.io/network/vnet2/network-object", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"network not found"}`, http.StatusNotFound)
+	})
+	tgc := newTestClient(t, mux, tg.ClientParams{})
+
+	config := terraform.NewResourceConfigRaw(map[string]any{"network": "vnet2", "name": "db", "cidr": "10.0.1.0/24"})
+	_, err := VNetObject().SimpleDiff(context.Background(), nil, config, tgc)
+	assert.NoError(t, err, "a network created in the same apply has no objects yet")
+}
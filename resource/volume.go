@@ -2,12 +2,12 @@ package resource
 
 import (
 	"context"
-	"errors"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/trustgrid/terraform-provider-tg/hcl"
+	"github.com/trustgrid/terraform-provider-tg/majordomo"
 	"github.com/trustgrid/terraform-provider-tg/tg"
 	"github.com/trustgrid/terraform-provider-tg/validators"
 )
@@ -25,6 +25,7 @@ func Volume() *schema.Resource {
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
 		CreateContext: r.Create,
+		Importer:      majordomo.Importer(r.Read, majordomo.AttributeID("name"), "node:{node_id}/{name}", "cluster:{cluster_fqdn}/{name}"),
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -126,8 +127,7 @@ func (vr *volume) Read(ctx context.Context, d *schema.ResourceData, meta any) di
 	}
 
 	if err := tgc.Get(ctx, vr.volumeURL(v), &v); err != nil {
-		var nferr *tg.NotFoundError
-		if errors.As(err, &nferr) {
+		if tg.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
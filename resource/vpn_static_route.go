@@ -71,6 +71,7 @@ func VPNStaticRoute() *schema.Resource {
 			RemoteID: func(route tg.VPNRoute) string {
 				return route.UID
 			},
+			ImportID: []string{"node:{node_id}/{network_name}/{uid}", "cluster:{cluster_fqdn}/{network_name}/{uid}"},
 		})
 
 	return &schema.Resource{
@@ -80,6 +81,7 @@ func VPNStaticRoute() *schema.Resource {
 		UpdateContext: md.Update,
 		DeleteContext: md.Delete,
 		CreateContext: md.Create,
+		Importer:      md.Importer(),
 
 		Schema: map[string]*schema.Schema{
 			"uid": {
@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -28,6 +28,13 @@ func ZTNAConfig() *schema.Resource {
 		ReadContext:   r.Read,
 		UpdateContext: r.Update,
 		DeleteContext: r.Delete,
+		Importer:      nodeOrClusterImporter(r.Read, nil, false),
+
+		// Generating or importing the wireguard key waits on the node itself.
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"node_id": {
@@ -232,9 +239,8 @@ func (z *ztnaConfig) Read(ctx context.Context, d *schema.ResourceData, meta any)
 		ztna.ClusterFQDN = gw.ClusterFQDN
 	}
 
-	var nferr *tg.NotFoundError
 	switch {
-	case errors.As(err, &nferr):
+	case tg.IsNotFound(err):
 		d.SetId("")
 		return nil
 	case err != nil:
@@ -8,10 +8,10 @@ type App struct {
 	GatewayNodeID       string   `json:"gatewayNode"`
 	IDPID               string   `json:"idpId"`
 	IP                  string   `json:"ip"`
-	Port                int      `json:"port,omitempty"`
+	Port                *int     `json:"port,omitempty"`
 	Protocol            string   `json:"protocol"`
 	Hostname            string   `json:"hostname,omitempty"`
-	SessionDuration     int      `json:"sessionDuration,omitempty"`
+	SessionDuration     *int     `json:"sessionDuration,omitempty"`
 	TLSVerificationMode string   `json:"tlsVerificationMode,omitempty"`
 	TrustMode           string   `json:"trustMode,omitempty"`
 	GroupIDs            []string `json:"groupIds,omitempty"`
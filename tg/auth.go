@@ -0,0 +1,121 @@
+package tg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenRefreshSkew is how long before its expiry a token is considered stale, so it doesn't
+// lapse while a request is in flight.
+const tokenRefreshSkew = time.Minute
+
+// ErrNoTokenPath is returned by NewClient for client credentials without a token path. The
+// portal's token endpoint isn't documented, so there's no default to fall back on.
+var ErrNoTokenPath = errors.New("client credentials require a token path (`token_path`)")
+
+type tokenReply struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// tokenExpiry reads the `exp` claim from a JWT without verifying it; the portal verifies it.
+func tokenExpiry(token string) (time.Time, bool) {
+	var claims jwt.StandardClaims
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.ExpiresAt, 0), true
+}
+
+// refreshable reports whether the client can obtain a new token on its own.
+func (tg *Client) refreshable() bool {
+	return tg.clientID != ""
+}
+
+// authHeader returns the Authorization header for the next request. With client credentials,
+// the token is refreshed when it's about to expire or when it matches `stale`, a token the
+// portal just rejected.
+func (tg *Client) authHeader(ctx context.Context, stale string) (string, error) {
+	if !tg.refreshable() {
+		if tg.JWT != "" {
+			return fmt.Sprintf("Bearer %s", tg.JWT), nil
+		}
+		return fmt.Sprintf("trustgrid-token %s:%s", tg.APIKey, tg.APISecret), nil
+	}
+
+	tg.tokenLock.Lock()
+	defer tg.tokenLock.Unlock()
+
+	if tg.JWT == "" || tg.JWT == stale || time.Now().Add(tokenRefreshSkew).After(tg.tokenExpiry) {
+		if err := tg.refreshToken(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("Bearer %s", tg.JWT), nil
+}
+
+// refreshToken exchanges the client credentials for a new JWT. Callers must hold tokenLock.
+func (tg *Client) refreshToken(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {tg.clientID},
+		"client_secret": {tg.clientSecret},
+	}
+
+	req, err := tg.newRequest(ctx, http.MethodPost, tg.tokenPath, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	r, err := tg.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error requesting token: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("error requesting token: %w", newAPIError(req.Method, tg.tokenPath, r))
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("error reading token reply: %w", err)
+	}
+
+	var reply tokenReply
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return fmt.Errorf("error decoding token reply: %w", err)
+	}
+	if reply.AccessToken == "" {
+		return errors.New("token reply didn't include an access token")
+	}
+
+	expiry, ok := tokenExpiry(reply.AccessToken)
+	switch {
+	case ok:
+	case reply.ExpiresIn > 0:
+		expiry = time.Now().Add(time.Duration(reply.ExpiresIn) * time.Second)
+	default:
+		return errors.New("token has no expiry")
+	}
+
+	tg.JWT = reply.AccessToken
+	tg.tokenExpiry = expiry
+
+	tflog.Debug(ctx, "refreshed portal token", map[string]any{"expires": expiry.Format(time.RFC3339)})
+
+	return nil
+}
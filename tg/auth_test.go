@@ -0,0 +1,77 @@
+package tg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedToken(t *testing.T, exp time.Time) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{ExpiresAt: exp.Unix()}).SignedString([]byte("test"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	got, ok := tokenExpiry(signedToken(t, exp))
+	require.True(t, ok)
+	assert.True(t, exp.Equal(got))
+
+	_, ok = tokenExpiry("not-a-jwt")
+	assert.False(t, ok)
+}
+
+func TestClient_ClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	var current atomic.Value
+	// The first token expires immediately, which forces a refresh before the next request.
+	expiries := []time.Duration{0, time.Hour, time.Hour}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+
+		n := issued.Add(1)
+		token := signedToken(t, time.Now().Add(expiries[n-1]))
+		current.Store(token)
+		_ = json.NewEncoder(w).Encode(tokenReply{AccessToken: token})
+	})
+
+	var revoked atomic.Bool
+	mux.HandleFunc("GET /api/node/abc", func(w http.ResponseWriter, r *http.Request) {
+		if revoked.Swap(false) || r.Header.Get("Authorization") != "Bearer "+current.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"uid":"abc"}`)
+	})
+
+	c := newTestClient(t, mux, ClientParams{ClientID: "id", ClientSecret: "secret", TokenPath: "/oauth2/token"})
+	require.Equal(t, int32(1), issued.Load())
+
+	require.NoError(t, c.Get(context.Background(), "/node/abc", &Node{}))
+	assert.Equal(t, int32(2), issued.Load(), "expired token is refreshed before the request")
+
+	revoked.Store(true)
+	require.NoError(t, c.Get(context.Background(), "/node/abc", &Node{}))
+	assert.Equal(t, int32(3), issued.Load(), "a 401 refreshes the token and retries once")
+}
+
+func TestNewClient_ClientCredentialsNeedTokenPath(t *testing.T) {
+	_, err := NewClient(context.Background(), ClientParams{ClientID: "id", ClientSecret: "secret"})
+	assert.ErrorIs(t, err, ErrNoTokenPath)
+}
@@ -0,0 +1,98 @@
+package tg
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Batcher coalesces items submitted for the same key within a short window and hands them to
+// a single flush, so resources touching one shared document during an apply write it once.
+type Batcher[T any] struct {
+	window time.Duration
+	flush  func(ctx context.Context, key string, items []T) []error
+
+	mu      sync.Mutex
+	pending map[string]*batch[T]
+}
+
+type batch[T any] struct {
+	ctx      context.Context
+	items    []T
+	dropped  []bool
+	flushing bool
+	errs     []error
+	done     chan struct{}
+}
+
+// NewBatcher returns a batcher that waits window after the first item for a key before calling
+// flush with every item queued for it. flush returns one error per item, in order.
+func NewBatcher[T any](window time.Duration, flush func(ctx context.Context, key string, items []T) []error) *Batcher[T] {
+	return &Batcher[T]{
+		window:  window,
+		flush:   flush,
+		pending: make(map[string]*batch[T]),
+	}
+}
+
+// Do queues item under key and blocks until its batch has been flushed, returning the error
+// flush reported for it. An item whose caller gives up before the flush starts is dropped from
+// the batch. Once the flush has started it can't be taken back, so Do waits for it and reports
+// what happened to the item, even if ctx is done by then.
+func (b *Batcher[T]) Do(ctx context.Context, key string, item T) error {
+	b.mu.Lock()
+	bt, ok := b.pending[key]
+	if !ok {
+		// The flush outlives whichever caller opened the batch.
+		bt = &batch[T]{ctx: context.WithoutCancel(ctx), done: make(chan struct{})}
+		b.pending[key] = bt
+		time.AfterFunc(b.window, func() { b.run(key, bt) })
+	}
+	i := len(bt.items)
+	bt.items = append(bt.items, item)
+	bt.dropped = append(bt.dropped, false)
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		b.mu.Lock()
+		if !bt.flushing {
+			bt.dropped[i] = true
+			b.mu.Unlock()
+			return ctx.Err()
+		}
+		b.mu.Unlock()
+		<-bt.done
+		return bt.errs[i]
+	case <-bt.done:
+		return bt.errs[i]
+	}
+}
+
+func (b *Batcher[T]) run(key string, bt *batch[T]) {
+	b.mu.Lock()
+	if b.pending[key] == bt {
+		delete(b.pending, key)
+	}
+	bt.flushing = true
+	var items []T
+	var indexes []int
+	for i, item := range bt.items {
+		if !bt.dropped[i] {
+			items = append(items, item)
+			indexes = append(indexes, i)
+		}
+	}
+	b.mu.Unlock()
+
+	bt.errs = make([]error, len(bt.items))
+	if len(items) > 0 {
+		errs := b.flush(bt.ctx, key, items)
+		for j, i := range indexes {
+			if j < len(errs) {
+				bt.errs[i] = errs[j]
+			}
+		}
+	}
+	close(bt.done)
+}
@@ -0,0 +1,121 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcher_CoalescesPerKey(t *testing.T) {
+	var mu sync.Mutex
+	flushed := map[string][]int{}
+
+	b := NewBatcher(50*time.Millisecond, func(_ context.Context, key string, items []int) []error {
+		mu.Lock()
+		defer mu.Unlock()
+		flushed[key] = append(flushed[key], len(items))
+
+		errs := make([]error, len(items))
+		for i, item := range items {
+			if item < 0 {
+				errs[i] = errors.New("negative")
+			}
+		}
+		return errs
+	})
+
+	var wg sync.WaitGroup
+	results := make([]error, 5)
+	for i, item := range []int{1, 2, -3, 4, 5} {
+		key := "node/a"
+		if i == 4 {
+			key = "node/b"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = b.Do(context.Background(), key, item)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string][]int{"node/a": {4}, "node/b": {1}}, flushed)
+	assert.NoError(t, results[0])
+	assert.NoError(t, results[1])
+	assert.EqualError(t, results[2], "negative")
+	assert.NoError(t, results[3])
+	assert.NoError(t, results[4])
+}
+
+func TestBatcher_NewBatchAfterFlush(t *testing.T) {
+	var flushes int
+	b := NewBatcher(time.Millisecond, func(_ context.Context, _ string, items []string) []error {
+		flushes++
+		return make([]error, len(items))
+	})
+
+	require.NoError(t, b.Do(context.Background(), "k", "one"))
+	require.NoError(t, b.Do(context.Background(), "k", "two"))
+	assert.Equal(t, 2, flushes)
+}
+
+func TestBatcher_CallerCanceled(t *testing.T) {
+	flushed := make(chan []string, 1)
+	b := NewBatcher(50*time.Millisecond, func(_ context.Context, _ string, items []string) []error {
+		flushed <- items
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, b.Do(context.Background(), "k", "two"))
+	}()
+	require.ErrorIs(t, b.Do(ctx, "k", "one"), context.Canceled)
+	wg.Wait()
+
+	assert.Equal(t, []string{"two"}, <-flushed, "a canceled caller's item isn't written")
+}
+
+func TestBatcher_AllCallersCanceled(t *testing.T) {
+	var flushes atomic.Int32
+	b := NewBatcher(time.Millisecond, func(_ context.Context, _ string, items []string) []error {
+		flushes.Add(1)
+		return make([]error, len(items))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, b.Do(ctx, "k", "one"), context.Canceled)
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, flushes.Load(), "an empty batch isn't flushed")
+}
+
+func TestBatcher_CanceledDuringFlush(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	b := NewBatcher(time.Millisecond, func(_ context.Context, _ string, _ []string) []error {
+		close(started)
+		<-release
+		return []error{errors.New("rejected")}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+		close(release)
+	}()
+
+	assert.EqualError(t, b.Do(ctx, "k", "one"), "rejected", "a flush that started reports what happened to the item")
+}
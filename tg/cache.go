@@ -0,0 +1,157 @@
+package tg
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheTTL is how long a node or cluster document is reused before it's fetched again.
+const DefaultCacheTTL = 30 * time.Second
+
+var (
+	// cacheableURL matches the node and cluster documents that many resources read just to
+	// pull out one piece of config.
+	cacheableURL = regexp.MustCompile(`^/(node|cluster)/[^/?]+$`)
+	// targetURL extracts the node or cluster a URL belongs to, so writes can invalidate it.
+	targetURL = regexp.MustCompile(`^/(?:v2/)?(node|cluster)/([^/?]+)`)
+)
+
+type noCacheKey struct{}
+
+// WithoutCache returns a context whose reads always go to the portal, for read-modify-write
+// cycles that must start from the current document.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// readCache holds node and cluster documents for a short time and coalesces concurrent
+// fetches of the same document into a single request.
+type readCache struct {
+	ttl    time.Duration
+	now    func() time.Time
+	flight singleflight.Group
+
+	mu          sync.Mutex
+	entries     map[string]cacheEntry
+	generations map[string]uint64
+}
+
+// newReadCache returns a cache keeping entries for ttl. A non-positive ttl disables caching.
+func newReadCache(ttl time.Duration) *readCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &readCache{
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]cacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+func cacheKey(url string) string {
+	return "/" + strings.TrimPrefix(url, "/")
+}
+
+func (c *readCache) cacheable(key string) bool {
+	return c != nil && cacheableURL.MatchString(key)
+}
+
+func (c *readCache) lookup(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || c.now().After(e.expires) {
+		return nil, false
+	}
+	return e.body, true
+}
+
+// get returns the cached body for url, or calls fetch once for all concurrent callers.
+func (c *readCache) get(ctx context.Context, url string, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	key := cacheKey(url)
+	if body, ok := c.lookup(key); ok {
+		tflog.Trace(ctx, "portal read cache hit", map[string]any{"url": key})
+		return body, nil
+	}
+
+	ch := c.flight.DoChan(key, func() (any, error) {
+		c.mu.Lock()
+		gen := c.generations[key]
+		c.generations[key] = gen
+		c.mu.Unlock()
+
+		// The fetch is shared, so one caller giving up mustn't fail the others.
+		body, err := fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// A write landed while we were fetching; the reply may predate it, so don't keep it.
+		if c.generations[key] == gen {
+			c.entries[key] = cacheEntry{body: body, expires: c.now().Add(c.ttl)}
+		}
+		return body, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		body, _ := res.Val.([]byte)
+		return body, nil
+	}
+}
+
+// invalidate drops whatever a write to url may have changed. Writes to a cluster also drop
+// every cached node, since members inherit cluster config.
+func (c *readCache) invalidate(url string) {
+	if c == nil {
+		return
+	}
+
+	m := targetURL.FindStringSubmatch(cacheKey(url))
+	if m == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	drop := func(key string) {
+		delete(c.entries, key)
+		c.generations[key]++
+		c.flight.Forget(key)
+	}
+
+	drop("/" + m[1] + "/" + m[2])
+	if m[1] == "cluster" {
+		for key := range c.generations {
+			if strings.HasPrefix(key, "/node/") {
+				drop(key)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package tg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCache_Cacheable(t *testing.T) {
+	c := newReadCache(time.Minute)
+
+	assert.True(t, c.cacheable(cacheKey("/node/abc")))
+	assert.True(t, c.cacheable(cacheKey("cluster/edge.example.com")))
+	assert.False(t, c.cacheable(cacheKey("/node/abc/config/network")))
+	assert.False(t, c.cacheable(cacheKey("/node/by-fqdn/edge1")))
+	assert.False(t, c.cacheable(cacheKey("/v2/alarm/abc")))
+
+	var disabled *readCache
+	assert.False(t, disabled.cacheable(cacheKey("/node/abc")))
+	disabled.invalidate("/node/abc")
+}
+
+func TestReadCache_ExpiresAndInvalidates(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newReadCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	var fetches int
+	fetch := func(context.Context) ([]byte, error) {
+		fetches++
+		return []byte(`{}`), nil
+	}
+
+	get := func(url string) {
+		_, err := c.get(context.Background(), url, fetch)
+		require.NoError(t, err)
+	}
+
+	get("/node/abc")
+	get("/node/abc")
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(2 * time.Minute)
+	get("/node/abc")
+	assert.Equal(t, 2, fetches, "expired entry is refetched")
+
+	c.invalidate("/v2/node/abc/config/services/svc-1")
+	get("/node/abc")
+	assert.Equal(t, 3, fetches, "write to a node sub-URL invalidates the node")
+
+	c.invalidate("/node/other/config/network")
+	get("/node/abc")
+	assert.Equal(t, 3, fetches, "writes to other nodes leave the entry alone")
+
+	c.invalidate("/cluster/edge.example.com/config/network")
+	get("/node/abc")
+	assert.Equal(t, 4, fetches, "cluster writes invalidate member nodes")
+}
+
+func TestClient_CoalescesNodeReads(t *testing.T) {
+	var gets atomic.Int32
+	release := make(chan struct{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/abc", func(w http.ResponseWriter, _ *http.Request) {
+		gets.Add(1)
+		<-release
+		_, _ = io.WriteString(w, `{"uid":"abc","name":"edge1"}`)
+	})
+	mux.HandleFunc("PUT /api/node/abc/config/network", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{}`)
+	})
+
+	c := newTestClient(t, mux, ClientParams{CacheTTL: time.Minute})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n Node
+			assert.NoError(t, c.Get(context.Background(), "/node/abc", &n))
+			assert.Equal(t, "edge1", n.Name)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), gets.Load(), "concurrent reads share one request")
+
+	require.NoError(t, c.Get(context.Background(), "/node/abc", &Node{}))
+	assert.Equal(t, int32(1), gets.Load(), "subsequent reads hit the cache")
+
+	_, err := c.Put(context.Background(), "/node/abc/config/network", map[string]string{})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.Background(), "/node/abc", &Node{}))
+	assert.Equal(t, int32(2), gets.Load(), "a write invalidates the node")
+}
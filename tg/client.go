@@ -9,34 +9,34 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Client struct {
-	Lock      sync.Mutex
-	writeLock sync.Mutex
-
 	APIHost   string
 	APIKey    string
 	APISecret string
 	JWT       string
 
 	Domain string
-}
-
-type NotFoundError struct {
-	URL string
-}
 
-type ValidationError struct {
-	URL string
-}
-
-func (e *ValidationError) Error() string {
-	return fmt.Sprintf("validation error: %s", e.URL)
-}
-
-func (e *NotFoundError) Error() string {
-	return fmt.Sprintf("not found: %s", e.URL)
+	// DeferNetworkCommits leaves virtual network changes staged for a tg_virtual_network_commit
+	// resource to commit, instead of committing them as each resource is applied.
+	DeferNetworkCommits bool
+
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	limiter    *rateLimiter
+	cache      *readCache
+	locks      lockManager
+	networks   networkRun
+
+	clientID     string
+	clientSecret string
+	tokenPath    string
+	tokenExpiry  time.Time
+	tokenLock    sync.Mutex
 }
 
 type ClientParams struct {
@@ -45,23 +45,66 @@ type ClientParams struct {
 	APIHost   string
 	JWT       string
 	OrgID     string
-}
 
-func doRequest(req *http.Request) (*http.Response, error) {
-	//nolint:gosec // provider endpoint is intentionally operator-configurable via TG_API_HOST/provider config
-	return http.DefaultClient.Do(req)
+	ClientID     string // ClientID and ClientSecret are exchanged for short-lived JWTs that are refreshed as they expire.
+	ClientSecret string
+	TokenPath    string // TokenPath is where client credentials are exchanged, relative to the portal URL. Required with ClientID.
+
+	BaseURL    string            // BaseURL is the full portal URL, including scheme and any path prefix. Overrides APIHost when set.
+	HTTPClient *http.Client      // HTTPClient sends requests to the portal. Defaults to http.DefaultClient.
+	Transport  http.RoundTripper // Transport is used to build the HTTP client when HTTPClient isn't set.
+
+	MaxRetries   int           // MaxRetries is how many times a throttled or failed request is retried. Zero disables retries.
+	RetryMaxWait time.Duration // RetryMaxWait caps the delay between retries. Defaults to DefaultRetryMaxWait.
+
+	RequestsPerSecond float64 // RequestsPerSecond limits the rate of portal requests, retries included. Zero disables limiting.
+	Burst             int     // Burst is how many requests may be sent at once before the rate applies. Defaults to RequestsPerSecond, rounded up.
+
+	CacheTTL time.Duration // CacheTTL is how long node and cluster documents are reused between reads. Zero disables the cache.
+
+	DeferNetworkCommits bool // DeferNetworkCommits stages virtual network changes without committing them.
 }
 
 func NewClient(ctx context.Context, params ClientParams) (*Client, error) {
+	if params.ClientID != "" && params.TokenPath == "" {
+		return nil, ErrNoTokenPath
+	}
+
+	base, err := baseURL(params)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := params.HTTPClient
+	switch {
+	case httpClient != nil:
+	case params.Transport != nil:
+		httpClient = &http.Client{Transport: params.Transport}
+	default:
+		httpClient = http.DefaultClient
+	}
+
 	client := &Client{
 		APIKey:    params.APIKey,
 		APISecret: params.APISecret,
 		APIHost:   params.APIHost,
 		JWT:       params.JWT,
+
+		DeferNetworkCommits: params.DeferNetworkCommits,
+
+		baseURL:    base,
+		httpClient: httpClient,
+		retry:      newRetryPolicy(params.MaxRetries, params.RetryMaxWait),
+		limiter:    newRateLimiter(params.RequestsPerSecond, params.Burst),
+		cache:      newReadCache(params.CacheTTL),
+
+		clientID:     params.ClientID,
+		clientSecret: params.ClientSecret,
+		tokenPath:    params.TokenPath,
 	}
 
 	org := Org{}
-	err := client.Get(ctx, "/org/mine", &org)
+	err = client.Get(ctx, "/org/mine", &org)
 	if err != nil {
 		return client, fmt.Errorf("error retrieving org info: %w", err)
 	}
@@ -82,61 +125,62 @@ func GetClient(meta any) *Client {
 	return tgc
 }
 
-func (tg *Client) authHeader() string {
-	if tg.JWT != "" {
-		return fmt.Sprintf("Bearer %s", tg.JWT)
-	}
-	return fmt.Sprintf("trustgrid-token %s:%s", tg.APIKey, tg.APISecret)
+// newRequest builds a request against the portal for the given path.
+func (tg *Client) newRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, method, tg.baseURL+"/"+strings.TrimPrefix(url, "/"), body)
 }
 
-func (tg *Client) Delete(ctx context.Context, url string, payload any) error {
-	tg.writeLock.Lock()
-	defer tg.writeLock.Unlock()
-
-	body, err := json.MarshalIndent(payload, "", "  ")
+// send authenticates and sends the request. Non-200 replies are returned as an *APIError
+// with the body already consumed; otherwise the caller owns the response body.
+func (tg *Client) send(req *http.Request, url string) (*http.Response, error) {
+	auth, err := tg.authHeader(req.Context(), "")
 	if err != nil {
-		return fmt.Errorf("couldn't marshal body: %w", err)
+		return nil, err
 	}
-	b := bytes.NewBuffer(body)
+	req.Header.Set("Authorization", auth)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("https://%s/%s", tg.APIHost, strings.TrimPrefix(url, "/")), b)
+	r, err := tg.doRequest(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if payload != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	req.Header.Set("Authorization", tg.authHeader())
-	req.Header.Set("Accept", "application/json")
+	if r.StatusCode == http.StatusUnauthorized && tg.refreshable() {
+		// The token may have been revoked or expired early; get a new one and try once more.
+		_, _ = io.Copy(io.Discard, r.Body)
+		r.Body.Close()
 
-	r, err := doRequest(req)
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-	if r.StatusCode != http.StatusOK {
-		reply, err := io.ReadAll(r.Body)
-		if err != nil {
-			return fmt.Errorf("[DELETE]: non-200 from portal (%s): %d; couldn't read body: %w", url, r.StatusCode, err)
+		stale := strings.TrimPrefix(auth, "Bearer ")
+		if auth, err = tg.authHeader(req.Context(), stale); err != nil {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", auth)
+
+		if r, err = tg.doRequest(req); err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("[DELETE]: non-200 from portal (%s): %d\npayload:\n%s\n\nreply:\n%s", url, r.StatusCode, string(body), reply)
 	}
 
-	return nil
+	if r.StatusCode != http.StatusOK {
+		defer r.Body.Close()
+		return nil, newAPIError(req.Method, url, r)
+	}
+	return r, nil
 }
 
-func (tg *Client) Post(ctx context.Context, url string, payload any) ([]byte, error) {
-	tg.writeLock.Lock()
-	defer tg.writeLock.Unlock()
+// write sends a JSON payload with the given method and returns the reply body.
+func (tg *Client) write(ctx context.Context, method string, url string, payload any) ([]byte, error) {
+	// Even a failed write may have changed something, so always drop cached reads.
+	defer tg.cache.invalidate(url)
 
 	body, err := json.MarshalIndent(payload, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("couldn't marshal body: %w", err)
 	}
-	b := bytes.NewBuffer(body)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://%s/%s", tg.APIHost, strings.TrimPrefix(url, "/")), b)
+	req, err := tg.newRequest(ctx, method, url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -144,120 +188,91 @@ func (tg *Client) Post(ctx context.Context, url string, payload any) ([]byte, er
 	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	req.Header.Set("Authorization", tg.authHeader())
 	req.Header.Set("Accept", "application/json")
 
-	r, err := doRequest(req)
+	r, err := tg.send(req, url)
 	if err != nil {
 		return nil, err
 	}
 	defer r.Body.Close()
+
 	reply, err := io.ReadAll(r.Body)
 	if err != nil {
-		return nil, fmt.Errorf("[POST] couldn't read body: %w", err)
-	}
-	if r.StatusCode != http.StatusOK {
-		return reply, fmt.Errorf("[POST] non-200 from portal (%s): %d\npayload:\n%s\n\nreply:\n%s", url, r.StatusCode, string(body), reply)
+		return nil, fmt.Errorf("[%s] couldn't read body: %w", method, err)
 	}
 
 	return reply, nil
 }
 
-func (tg *Client) Put(ctx context.Context, url string, payload any) ([]byte, error) {
-	tg.writeLock.Lock()
-	defer tg.writeLock.Unlock()
+func (tg *Client) Delete(ctx context.Context, url string, payload any) error {
+	_, err := tg.write(ctx, http.MethodDelete, url, payload)
+	return err
+}
 
-	body, err := json.MarshalIndent(payload, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("couldn't marshal body: %w", err)
-	}
-	b := bytes.NewBuffer(body)
+func (tg *Client) Post(ctx context.Context, url string, payload any) ([]byte, error) {
+	return tg.write(ctx, http.MethodPost, url, payload)
+}
+
+func (tg *Client) Put(ctx context.Context, url string, payload any) ([]byte, error) {
+	return tg.write(ctx, http.MethodPut, url, payload)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("https://%s/%s", tg.APIHost, strings.TrimPrefix(url, "/")), b)
+func (tg *Client) RawGet(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := tg.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if payload != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	req.Header.Set("Authorization", tg.authHeader())
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
 
-	r, err := doRequest(req)
+	r, err := tg.send(req, url)
 	if err != nil {
 		return nil, err
 	}
-	defer r.Body.Close()
-	reply, err := io.ReadAll(r.Body)
-	if err != nil {
-		return nil, fmt.Errorf("[PUT] couldn't read body: %w", err)
-	}
-	if r.StatusCode != http.StatusOK {
-		return reply, fmt.Errorf("[PUT] non-200 from portal (%s): %d\npayload:\n%s\n\nreply:\n%s", url, r.StatusCode, string(body), reply)
-	}
 
-	return reply, nil
+	return r.Body, nil
 }
 
-func (tg *Client) RawGet(ctx context.Context, url string) (io.ReadCloser, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/%s", tg.APIHost, strings.TrimPrefix(url, "/")), nil)
+// fetch GETs the given URL and returns the reply body.
+func (tg *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := tg.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", tg.authHeader())
+	req.Header.Set("Accept", "application/json")
 
-	r, err := doRequest(req)
+	r, err := tg.send(req, url)
 	if err != nil {
 		return nil, err
 	}
+	defer r.Body.Close()
 
-	if r.StatusCode != http.StatusOK {
-		switch r.StatusCode {
-		case http.StatusNotFound:
-			return r.Body, &NotFoundError{URL: url}
-		case http.StatusUnprocessableEntity:
-			return r.Body, &ValidationError{URL: url}
-		default:
-			return r.Body, fmt.Errorf("[RAWGET] non-200 from portal (%s): %d; couldn't read body: %w", url, r.StatusCode, err)
-		}
+	reply, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading reply: %w", err)
 	}
 
-	return r.Body, nil
+	return reply, nil
 }
 
+// Get decodes the JSON reply from the given URL into out. Node and cluster documents are
+// served from the client's read cache when it's enabled, unless ctx comes from WithoutCache.
 func (tg *Client) Get(ctx context.Context, url string, out any) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/%s", tg.APIHost, strings.TrimPrefix(url, "/")), nil)
-	if err != nil {
-		return err
+	var reply []byte
+	var err error
+
+	if tg.cache.cacheable(cacheKey(url)) && !cacheBypassed(ctx) {
+		reply, err = tg.cache.get(ctx, url, func(ctx context.Context) ([]byte, error) {
+			return tg.fetch(ctx, url)
+		})
+	} else {
+		reply, err = tg.fetch(ctx, url)
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", tg.authHeader())
-	req.Header.Set("Accept", "application/json")
-
-	r, err := doRequest(req)
 	if err != nil {
 		return err
 	}
-	defer r.Body.Close()
-	if r.StatusCode != http.StatusOK {
-		reply, err := io.ReadAll(r.Body)
-		if err != nil {
-			return fmt.Errorf("[GET] non-200 from portal (%s): %d; couldn't read body: %w", url, r.StatusCode, err)
-		}
-		if r.StatusCode == http.StatusNotFound {
-			return &NotFoundError{URL: url}
-		}
-		return fmt.Errorf("[GET] non-200 from portal (%s): %d - %s\n%s", url, r.StatusCode, req.URL.String(), reply)
-	}
-
-	reply, err := io.ReadAll(r.Body)
-	if err != nil {
-		return fmt.Errorf("error reading reply: %w", err)
-	}
 
 	err = json.Unmarshal(reply, out)
 	if err != nil {
@@ -0,0 +1,114 @@
+package tg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient starts a plain-HTTP stand-in for the portal mounted under /api and
+// returns a client pointed at it.
+func newTestClient(t *testing.T, mux *http.ServeMux, params ClientParams) *Client {
+	t.Helper()
+
+	mux.HandleFunc("GET /api/org/mine", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"uid":"org-1","domain":"example.trustgrid.io"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	params.BaseURL = srv.URL + "/api/"
+	params.HTTPClient = srv.Client()
+
+	c, err := NewClient(context.Background(), params)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_BaseURLWithPathPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/abc", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trustgrid-token key:secret", r.Header.Get("Authorization"))
+		_, _ = io.WriteString(w, `{"uid":"abc","name":"edge1"}`)
+	})
+
+	c := newTestClient(t, mux, ClientParams{APIKey: "key", APISecret: "secret"})
+	assert.Equal(t, "example.trustgrid.io", c.Domain)
+
+	var n Node
+	require.NoError(t, c.Get(context.Background(), "/node/abc", &n))
+	assert.Equal(t, "edge1", n.Name)
+}
+
+func TestClient_APIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/node/abc/config/network", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = io.WriteString(w, `{"message":"bad route"}`)
+	})
+
+	c := newTestClient(t, mux, ClientParams{})
+
+	_, err := c.Put(context.Background(), "/node/abc/config/network", map[string]string{})
+	require.Error(t, err)
+	assert.True(t, IsUnprocessable(err))
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.MethodPut, apiErr.Method)
+	assert.Equal(t, "/node/abc/config/network", apiErr.URL)
+	assert.Equal(t, "bad route", apiErr.Message)
+	assert.Equal(t, "req-42", apiErr.RequestID)
+
+	err = c.Get(context.Background(), "/node/missing", &Node{})
+	assert.True(t, IsNotFound(err))
+}
+
+func TestClient_RetriesThrottledRequests(t *testing.T) {
+	var gets, posts atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/node/abc", func(w http.ResponseWriter, _ *http.Request) {
+		if gets.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, `{"uid":"abc"}`)
+	})
+	mux.HandleFunc("POST /api/node/abc/things", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name":"thing"}`, string(body), "body must be resent on retry")
+		if posts.Add(1) < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, `{}`)
+	})
+	mux.HandleFunc("POST /api/node/abc/broken", func(w http.ResponseWriter, _ *http.Request) {
+		posts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	c := newTestClient(t, mux, ClientParams{MaxRetries: 3})
+
+	require.NoError(t, c.Get(context.Background(), "/node/abc", &Node{}))
+	assert.Equal(t, int32(3), gets.Load())
+
+	_, err := c.Post(context.Background(), "/node/abc/things", map[string]string{"name": "thing"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), posts.Load(), "POST is retried on a 429")
+
+	posts.Store(0)
+	_, err = c.Post(context.Background(), "/node/abc/broken", map[string]string{"name": "thing"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), posts.Load(), "POST isn't retried on a 502")
+}
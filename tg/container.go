@@ -51,13 +51,13 @@ type ULimit struct {
 }
 
 type ContainerLimits struct {
-	CPUMax  int `json:"cpuMax,omitempty"`
-	IORBPS  int `json:"ioRbps,omitempty"`
-	IOWBPS  int `json:"ioWbps,omitempty"`
-	IORIOPS int `json:"ioRiops,omitempty"`
-	IOWIOPS int `json:"ioWiops,omitempty"`
-	MemHigh int `json:"memHigh,omitempty"`
-	MemMax  int `json:"memMax,omitempty"`
+	CPUMax  *int `json:"cpuMax,omitempty"`
+	IORBPS  *int `json:"ioRbps,omitempty"`
+	IOWBPS  *int `json:"ioWbps,omitempty"`
+	IORIOPS *int `json:"ioRiops,omitempty"`
+	IOWIOPS *int `json:"ioWiops,omitempty"`
+	MemHigh *int `json:"memHigh,omitempty"`
+	MemMax  *int `json:"memMax,omitempty"`
 
 	Limits []ULimit `json:"limits,omitempty"`
 }
@@ -0,0 +1,170 @@
+package tg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// requestIDHeaders are the headers the portal (and the gateways in front of it) use to
+// identify a request, in order of preference.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"X-Amzn-Requestid",
+	"X-Amz-Apigw-Id",
+	"X-Amzn-Trace-Id",
+}
+
+// APIError is returned by every Client verb when the portal replies with a non-200 status.
+type APIError struct {
+	Method     string // Method is the HTTP verb of the failed request.
+	URL        string // URL is the portal path that was requested.
+	StatusCode int    // StatusCode is the HTTP status the portal replied with.
+	Message    string // Message is the error message parsed from the portal reply, if any.
+	Body       []byte // Body is the raw reply body.
+	RequestID  string // RequestID is the request/correlation ID the portal assigned, if any.
+
+	// Fields are the validation failures the portal tied to fields of the request body, if any.
+	Fields []FieldError
+}
+
+// FieldError is a validation failure the portal tied to a field of the request body.
+type FieldError struct {
+	Field   string // Field is the path of the field in the request body, like `vrfs[0].acls[2].source`.
+	Message string // Message is what's wrong with it.
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, " (request id %s)", e.RequestID)
+	}
+	return sb.String()
+}
+
+// NotFoundError is returned when a lookup doesn't find the requested record. Client verbs
+// report a 404 as an *APIError instead; use IsNotFound to check for both.
+type NotFoundError struct {
+	URL string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("not found: %s", e.URL)
+}
+
+// newAPIError builds an APIError from a non-200 portal reply, consuming its body.
+func newAPIError(method string, url string, r *http.Response) *APIError {
+	e := &APIError{
+		Method:     method,
+		URL:        url,
+		StatusCode: r.StatusCode,
+	}
+
+	for _, h := range requestIDHeaders {
+		if id := r.Header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		e.Message = fmt.Sprintf("couldn't read body: %s", err)
+		return e
+	}
+	e.Body = body
+	e.Message = parseErrorMessage(body)
+	e.Fields = parseFieldErrors(body)
+
+	return e
+}
+
+// parseErrorMessage extracts the human-readable message from a portal error reply.
+// The portal isn't consistent about the shape, so a few common ones are tried before
+// falling back to the raw body.
+func parseErrorMessage(body []byte) string {
+	var reply struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &reply); err == nil {
+		switch {
+		case reply.Message != "":
+			return reply.Message
+		case reply.Error != "":
+			return reply.Error
+		case len(reply.Errors) > 0:
+			msgs := make([]string, 0, len(reply.Errors))
+			for _, e := range reply.Errors {
+				msgs = append(msgs, e.Message)
+			}
+			return strings.Join(msgs, "; ")
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// parseFieldErrors extracts the per-field validation failures from a portal error reply, which
+// name the field as either `field` or `path`.
+func parseFieldErrors(body []byte) []FieldError {
+	var reply struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+			Path    string `json:"path"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return nil
+	}
+
+	var fields []FieldError
+	for _, e := range reply.Errors {
+		field := e.Field
+		if field == "" {
+			field = e.Path
+		}
+		if field != "" {
+			fields = append(fields, FieldError{Field: field, Message: e.Message})
+		}
+	}
+	return fields
+}
+
+// StatusCode returns the HTTP status of the portal reply that caused err, or 0 if err
+// isn't an *APIError.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 from the portal or a failed lookup.
+func IsNotFound(err error) bool {
+	var nferr *NotFoundError
+	return errors.As(err, &nferr) || StatusCode(err) == http.StatusNotFound
+}
+
+// IsConflict reports whether err is a 409 from the portal.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsUnprocessable reports whether err is a 422 from the portal, which it uses for
+// validation failures.
+func IsUnprocessable(err error) bool {
+	return StatusCode(err) == http.StatusUnprocessableEntity
+}
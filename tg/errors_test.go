@@ -0,0 +1,106 @@
+package tg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reply(status int, body string, headers map[string]string) *http.Response {
+	r := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name      string
+		resp      *http.Response
+		message   string
+		requestID string
+	}{
+		{
+			name:      "message field",
+			resp:      reply(422, `{"message":"invalid cidr"}`, map[string]string{"X-Amzn-Requestid": "abc-123"}),
+			message:   "invalid cidr",
+			requestID: "abc-123",
+		},
+		{
+			name:    "error field",
+			resp:    reply(409, `{"error":"already exists"}`, nil),
+			message: "already exists",
+		},
+		{
+			name:    "errors list",
+			resp:    reply(422, `{"errors":[{"message":"one"},{"message":"two"}]}`, nil),
+			message: "one; two",
+		},
+		{
+			name:      "plain text",
+			resp:      reply(502, "Bad Gateway\n", map[string]string{"X-Request-Id": "req-1"}),
+			message:   "Bad Gateway",
+			requestID: "req-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(http.MethodPut, "/node/abc", tt.resp)
+			assert.Equal(t, http.MethodPut, err.Method)
+			assert.Equal(t, "/node/abc", err.URL)
+			assert.Equal(t, tt.resp.StatusCode, err.StatusCode)
+			assert.Equal(t, tt.message, err.Message)
+			assert.Equal(t, tt.requestID, err.RequestID)
+			assert.NotEmpty(t, err.Body)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	notFound := fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusNotFound})
+	conflict := &APIError{StatusCode: http.StatusConflict}
+	invalid := &APIError{StatusCode: http.StatusUnprocessableEntity}
+
+	assert.True(t, IsNotFound(notFound))
+	assert.True(t, IsNotFound(&NotFoundError{URL: "route"}))
+	assert.False(t, IsNotFound(conflict))
+	assert.False(t, IsNotFound(errors.New("boom")))
+
+	assert.True(t, IsConflict(conflict))
+	assert.False(t, IsConflict(invalid))
+
+	assert.True(t, IsUnprocessable(invalid))
+	assert.False(t, IsUnprocessable(nil))
+
+	require.Equal(t, http.StatusNotFound, StatusCode(notFound))
+	require.Equal(t, 0, StatusCode(errors.New("boom")))
+}
+
+func TestNewAPIError_Fields(t *testing.T) {
+	err := newAPIError(http.MethodPut, "/node/abc/config/network", reply(422, `{"errors":[
+		{"message":"invalid cidr","field":"vrfs[0].acls[2].source"},
+		{"message":"unknown nic","path":"interfaces.1.nic"},
+		{"message":"config is locked"}
+	]}`, nil))
+
+	assert.Equal(t, "invalid cidr; unknown nic; config is locked", err.Message)
+	assert.Equal(t, []FieldError{
+		{Field: "vrfs[0].acls[2].source", Message: "invalid cidr"},
+		{Field: "interfaces.1.nic", Message: "unknown nic"},
+	}, err.Fields)
+
+	assert.Empty(t, newAPIError(http.MethodPut, "/node/abc", reply(422, `{"message":"invalid"}`, nil)).Fields)
+}
@@ -11,7 +11,7 @@ type KVMImage struct {
 type KVMVolume struct {
 	Name          string `json:"name"`
 	DeviceType    string `json:"deviceType"`
-	DeviseBus     string `json:"deviceBus"`
+	DeviceBus     string `json:"deviceBus"`
 	Size          int    `json:"size,omitempty"`
 	ProvisionType string `json:"provisionType"`
 	Path          string `json:"path,omitempty"`
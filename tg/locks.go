@@ -0,0 +1,87 @@
+package tg
+
+import (
+	"regexp"
+	"sync"
+)
+
+// OrgTarget is the lock target for org-level records that don't belong to a node, cluster or network.
+const OrgTarget = "org"
+
+var networkURL = regexp.MustCompile(`^/v2/domain/[^/]+/network/([^/?]+)`)
+
+// NodeTarget returns the lock target for a node.
+func NodeTarget(id string) string {
+	return "node/" + id
+}
+
+// ClusterTarget returns the lock target for a cluster.
+func ClusterTarget(fqdn string) string {
+	return "cluster/" + fqdn
+}
+
+// NetworkTarget returns the lock target for a virtual network.
+func NetworkTarget(name string) string {
+	return "network/" + name
+}
+
+// TargetOf returns the lock target a portal URL writes to: the node, cluster or virtual
+// network it belongs to, or OrgTarget for everything else.
+func TargetOf(url string) string {
+	key := cacheKey(url)
+	if m := targetURL.FindStringSubmatch(key); m != nil {
+		return m[1] + "/" + m[2]
+	}
+	if m := networkURL.FindStringSubmatch(key); m != nil {
+		return NetworkTarget(m[1])
+	}
+	return OrgTarget
+}
+
+type targetLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lockManager hands out one mutex per target, so read-modify-write cycles against the same
+// node, cluster or network are serialized while unrelated targets proceed in parallel.
+type lockManager struct {
+	mu    sync.Mutex
+	locks map[string]*targetLock
+}
+
+func (m *lockManager) lock(target string) func() {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*targetLock)
+	}
+	l, ok := m.locks[target]
+	if !ok {
+		l = &targetLock{}
+		m.locks[target] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(m.locks, target)
+		}
+	}
+}
+
+// LockTarget blocks until the caller holds the lock for target and returns the function that
+// releases it. Hold it across read-modify-write cycles:
+//
+//	unlock := tgc.LockTarget(tg.NodeTarget(nodeID))
+//	defer unlock()
+func (tg *Client) LockTarget(target string) func() {
+	return tg.locks.lock(target)
+}
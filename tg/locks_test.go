@@ -0,0 +1,64 @@
+package tg
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetOf(t *testing.T) {
+	tests := map[string]string{
+		"/node/abc/config/network":                           "node/abc",
+		"/v2/node/abc/config/services/svc-1":                 "node/abc",
+		"cluster/edge.example.com/config/connectors":         "cluster/edge.example.com",
+		"/v2/domain/example.trustgrid.io/network/corp/route": "network/corp",
+		"/v2/domain/example.trustgrid.io/network":            OrgTarget,
+		"/v2/alarm": OrgTarget,
+	}
+
+	for url, want := range tests {
+		assert.Equal(t, want, TargetOf(url), url)
+	}
+}
+
+func TestLockManager(t *testing.T) {
+	var m lockManager
+
+	var inside, maxInside atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := m.lock(NodeTarget("abc"))
+			defer unlock()
+
+			n := inside.Add(1)
+			if n > maxInside.Load() {
+				maxInside.Store(n)
+			}
+			time.Sleep(5 * time.Millisecond)
+			inside.Add(-1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxInside.Load(), "same target is serialized")
+
+	unlock := m.lock(NodeTarget("abc"))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.lock(NodeTarget("other"))()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock on a different target blocked")
+	}
+	unlock()
+
+	assert.Empty(t, m.locks, "released locks are dropped")
+}
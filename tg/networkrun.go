@@ -0,0 +1,54 @@
+package tg
+
+import "sync"
+
+// networkRun tracks what resources plan and apply against virtual networks over the life of a
+// client. Terraform configures a new provider, and so a new client, for every plan and apply, so
+// none of it outlives the run it was recorded in.
+type networkRun struct {
+	mu      sync.Mutex
+	planned map[string][]any
+	retired map[string]bool
+}
+
+// PlanNetworkChange records a change a resource plans to make to a virtual network's records, so
+// the plans of other resources in the same run can account for it. record is the record as it
+// will be once the change is applied.
+func (tg *Client) PlanNetworkChange(network string, record any) {
+	tg.networks.mu.Lock()
+	defer tg.networks.mu.Unlock()
+
+	if tg.networks.planned == nil {
+		tg.networks.planned = make(map[string][]any)
+	}
+	tg.networks.planned[network] = append(tg.networks.planned[network], record)
+}
+
+// PlannedNetworkChanges returns the records planned for a virtual network so far, in the order
+// they were planned.
+func (tg *Client) PlannedNetworkChanges(network string) []any {
+	tg.networks.mu.Lock()
+	defer tg.networks.mu.Unlock()
+
+	return append([]any(nil), tg.networks.planned[network]...)
+}
+
+// SetNetworkCommitRetired records whether a virtual network's commit resource has been destroyed,
+// leaving nothing to commit the changes staged on it.
+func (tg *Client) SetNetworkCommitRetired(network string, retired bool) {
+	tg.networks.mu.Lock()
+	defer tg.networks.mu.Unlock()
+
+	if tg.networks.retired == nil {
+		tg.networks.retired = make(map[string]bool)
+	}
+	tg.networks.retired[network] = retired
+}
+
+// NetworkCommitRetired reports whether the network's commit resource has been destroyed.
+func (tg *Client) NetworkCommitRetired(network string) bool {
+	tg.networks.mu.Lock()
+	defer tg.networks.mu.Unlock()
+
+	return tg.networks.retired[network]
+}
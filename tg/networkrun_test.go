@@ -0,0 +1,37 @@
+package tg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlannedNetworkChanges(t *testing.T) {
+	var c Client
+
+	assert.Empty(t, c.PlannedNetworkChanges("corp"))
+
+	c.PlanNetworkChange("corp", VNetObject{Name: "db"})
+	c.PlanNetworkChange("lab", VNetGroup{Name: "servers"})
+	c.PlanNetworkChange("corp", VNetGroup{Name: "servers"})
+
+	planned := c.PlannedNetworkChanges("corp")
+	assert.Equal(t, []any{VNetObject{Name: "db"}, VNetGroup{Name: "servers"}}, planned)
+
+	planned[0] = nil
+	assert.Equal(t, VNetObject{Name: "db"}, c.PlannedNetworkChanges("corp")[0], "callers get a copy")
+}
+
+func TestNetworkCommitRetired(t *testing.T) {
+	var c, other Client
+
+	assert.False(t, c.NetworkCommitRetired("corp"))
+
+	c.SetNetworkCommitRetired("corp", true)
+	assert.True(t, c.NetworkCommitRetired("corp"))
+	assert.False(t, c.NetworkCommitRetired("lab"))
+	assert.False(t, other.NetworkCommitRetired("corp"), "each client, and so each provider configuration, keeps its own")
+
+	c.SetNetworkCommitRetired("corp", false)
+	assert.False(t, c.NetworkCommitRetired("corp"))
+}
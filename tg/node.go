@@ -53,11 +53,11 @@ type GatewayConfig struct {
 
 	Enabled            bool   `json:"enabled"`
 	Host               string `json:"host,omitempty"`
-	Port               int    `json:"port,omitempty"`
+	Port               *int   `json:"port,omitempty"`
 	MaxMBPS            int    `json:"maxmbps,omitempty"`
 	ConnectToPublic    bool   `json:"connectToPublic"`
 	Type               string `json:"type,omitempty"`
-	MonitorHops        bool   `json:"monitorHops,omitempty"`
+	MonitorHops        *bool  `json:"monitorHops,omitempty"`
 	MaxClientWriteMBPS int    `json:"maxClientWriteMbps,omitempty"`
 
 	UDPEnabled bool `json:"udpEnabled"`
@@ -248,14 +248,14 @@ type NetworkInterface struct {
 	ClusterRouteTables []string       `json:"clusterRouteTables,omitempty"`
 	SubInterfaces      []SubInterface `json:"subInterfaces,omitempty"`
 	ClusterIP          string         `json:"clusterIP,omitempty"`
-	DHCP               bool           `json:"dhcp,omitempty"`
+	DHCP               *bool          `json:"dhcp,omitempty"`
 	Gateway            string         `json:"gateway,omitempty"`
 	IP                 string         `json:"ip,omitempty"`
 	Mode               string         `json:"mode,omitempty"`
 	DNS                []string       `json:"dns,omitempty"`
 	Duplex             string         `json:"duplex,omitempty"`
 	Speed              int            `json:"speed,omitempty"`
-	MTU                int            `json:"mtu,omitempty"`
+	MTU                *int           `json:"mtu,omitempty"`
 }
 
 type VRFACL struct {
@@ -275,11 +275,12 @@ type VRFRoute struct {
 }
 
 type VRFNAT struct {
-	Source     string `json:"source,omitempty"`
-	Dest       string `json:"dest,omitempty"`
-	Masquerade bool   `json:"masquerade"`
-	ToSource   string `json:"toSource,omitempty"`
-	ToDest     string `json:"toDest,omitempty"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Dest        string `json:"dest,omitempty"`
+	Masquerade  bool   `json:"masquerade"`
+	ToSource    string `json:"toSource,omitempty"`
+	ToDest      string `json:"toDest,omitempty"`
 }
 
 type VRFRule struct {
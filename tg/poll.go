@@ -0,0 +1,90 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultPollInterval is the delay before the first re-check of a condition.
+	DefaultPollInterval = 2 * time.Second
+	// DefaultPollMaxInterval caps the delay between two checks of a condition.
+	DefaultPollMaxInterval = 30 * time.Second
+)
+
+// ErrPollTimeout is returned by `Poll` when the condition isn't met before the timeout or the
+// context deadline.
+var ErrPollTimeout = errors.New("timed out waiting for condition")
+
+// ConditionFunc reports whether whatever is being waited for has happened. A non-nil error stops
+// the polling and is returned as is.
+type ConditionFunc func(ctx context.Context) (bool, error)
+
+// Poller checks a condition until it is met, backing off between checks.
+type Poller struct {
+	Interval    time.Duration // Interval is the delay before the first re-check. Defaults to `DefaultPollInterval`.
+	MaxInterval time.Duration // MaxInterval caps the delay, which doubles after every check. Defaults to `DefaultPollMaxInterval`.
+	Timeout     time.Duration // Timeout bounds the whole wait. If zero, only the context deadline applies.
+}
+
+// Poll checks cond immediately and then after every interval until it returns true or an error.
+// When the context is cancelled or the timeout passes first, the returned error wraps both
+// `ErrPollTimeout` and the context error.
+func (p Poller) Poll(ctx context.Context, cond ConditionFunc) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	maxInterval := p.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultPollMaxInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		done, err := cond(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// The condition most likely failed because its request was cut short.
+			return fmt.Errorf("%w: %w", ErrPollTimeout, ctx.Err())
+		case err != nil:
+			return err
+		case done:
+			return nil
+		}
+
+		tflog.Debug(ctx, "condition not met, polling again", map[string]any{
+			"attempt": attempt,
+			"wait":    interval.String(),
+		})
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ErrPollTimeout, ctx.Err())
+		case <-timer.C:
+		}
+
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// Poll checks cond with the default intervals until it is met or ctx is done.
+func Poll(ctx context.Context, cond ConditionFunc) error {
+	return Poller{}.Poll(ctx, cond)
+}
@@ -0,0 +1,70 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoller_Poll(t *testing.T) {
+	p := Poller{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+
+	calls := 0
+	err := p.Poll(t.Context(), func(context.Context) (bool, error) {
+		calls++
+		return calls == 5, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestPoller_PollError(t *testing.T) {
+	p := Poller{Interval: time.Millisecond}
+	boom := errors.New("boom")
+
+	calls := 0
+	err := p.Poll(t.Context(), func(context.Context) (bool, error) {
+		calls++
+		if calls == 2 {
+			return false, boom
+		}
+		return false, nil
+	})
+	require.ErrorIs(t, err, boom)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPoller_PollTimeout(t *testing.T) {
+	p := Poller{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}
+
+	start := time.Now()
+	err := p.Poll(t.Context(), func(context.Context) (bool, error) { return false, nil })
+	require.ErrorIs(t, err, ErrPollTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestPoller_PollCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+
+	err := Poller{Interval: time.Hour}.Poll(ctx, func(context.Context) (bool, error) {
+		cancel()
+		return false, nil
+	})
+	require.ErrorIs(t, err, ErrPollTimeout)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPoller_PollConditionCutShort(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+
+	err := Poller{}.Poll(ctx, func(ctx context.Context) (bool, error) {
+		cancel()
+		return false, ctx.Err()
+	})
+	require.ErrorIs(t, err, ErrPollTimeout, "a request failing because the wait ran out is a timeout")
+}
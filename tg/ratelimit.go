@@ -0,0 +1,138 @@
+package tg
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// rateLimiter is a token bucket shared by every request the client sends. It refills at
+// `rate` tokens per second up to `burst` tokens; each request takes one.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+
+	queued   time.Duration
+	requests int
+	waits    int
+}
+
+// queueStats describes how the client's rate limiter has delayed requests so far.
+type queueStats struct {
+	Requests int           // Requests is the number of requests that went through the limiter.
+	Queued   int           // Queued is the number of requests that had to wait for a token.
+	Total    time.Duration // Total is the combined time requests spent waiting.
+}
+
+// newRateLimiter returns a limiter allowing `rps` requests per second with bursts of `burst`.
+// A non-positive rate disables limiting. A non-positive burst defaults to the rate, rounded up.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(rps))
+	}
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token, going into debt if none is available, and returns how long the
+// caller has to wait before its token is actually due.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	l.requests++
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel hands back a token taken by reserve for a request that was never sent.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+func (l *rateLimiter) record(wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waits++
+	l.queued += wait
+}
+
+// Wait blocks until the next request may be sent or ctx is done, and returns how long it waited.
+func (l *rateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	wait := l.reserve()
+	if wait == 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return 0, ctx.Err()
+	case <-timer.C:
+	}
+
+	l.record(wait)
+	return wait, nil
+}
+
+func (l *rateLimiter) stats() queueStats {
+	if l == nil {
+		return queueStats{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return queueStats{Requests: l.requests, Queued: l.waits, Total: l.queued}
+}
+
+// throttle waits for the rate limiter before a request is sent, logging any delay along with
+// how much the limiter has queued requests so far.
+func (tg *Client) throttle(ctx context.Context, method string, url string) error {
+	wait, err := tg.limiter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		stats := tg.limiter.stats()
+		tflog.Debug(ctx, "portal request queued by rate limiter", map[string]any{
+			"method":          method,
+			"url":             url,
+			"queued":          wait.String(),
+			"total_queued":    stats.Total.String(),
+			"queued_requests": stats.Queued,
+			"requests":        stats.Requests,
+		})
+	}
+	return nil
+}
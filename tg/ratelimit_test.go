@@ -0,0 +1,62 @@
+package tg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	l := newRateLimiter(0, 10)
+	assert.Nil(t, l)
+
+	wait, err := l.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+	assert.Equal(t, queueStats{}, l.stats())
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(2, 2)
+	l.now = func() time.Time { return now }
+
+	assert.Zero(t, l.reserve(), "burst token")
+	assert.Zero(t, l.reserve(), "burst token")
+	assert.Equal(t, 500*time.Millisecond, l.reserve(), "one token short at 2/s")
+	assert.Equal(t, time.Second, l.reserve(), "two tokens short at 2/s")
+
+	now = now.Add(10 * time.Second)
+	assert.Zero(t, l.reserve(), "bucket refilled")
+	assert.Equal(t, 5, l.stats().Requests)
+}
+
+func TestRateLimiter_WaitRespectsContext(t *testing.T) {
+	l := newRateLimiter(0.001, 1)
+	_, err := l.Wait(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = l.Wait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, l.stats().Queued)
+}
+
+func TestRateLimiter_WaitRecordsQueueTime(t *testing.T) {
+	l := newRateLimiter(100, 1)
+
+	for i := 0; i < 3; i++ {
+		_, err := l.Wait(context.Background())
+		require.NoError(t, err)
+	}
+
+	stats := l.stats()
+	assert.Equal(t, 3, stats.Requests)
+	assert.Equal(t, 2, stats.Queued)
+	assert.Positive(t, stats.Total)
+}
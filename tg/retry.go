@@ -0,0 +1,208 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed request is retried when the provider doesn't say otherwise.
+	DefaultMaxRetries = 5
+	// DefaultRetryMaxWait caps the delay between two attempts of the same request.
+	DefaultRetryMaxWait = 30 * time.Second
+
+	retryMinWait = 500 * time.Millisecond
+)
+
+// RetryPolicy decides whether and when a failed portal request is sent again.
+//
+// GET, PUT and DELETE are idempotent against the portal and are retried on throttling,
+// gateway errors and connection resets. POST is only retried when the portal tells us
+// it didn't process the request: a 429, or a 503 that carries a Retry-After header.
+type RetryPolicy struct {
+	MaxRetries int           // MaxRetries is the number of additional attempts after the first one. Zero disables retries.
+	MaxWait    time.Duration // MaxWait caps both the exponential backoff and any Retry-After the portal asks for.
+	MinWait    time.Duration // MinWait is the base delay for the exponential backoff.
+}
+
+func newRetryPolicy(maxRetries int, maxWait time.Duration) RetryPolicy {
+	if maxWait <= 0 {
+		maxWait = DefaultRetryMaxWait
+	}
+	minWait := retryMinWait
+	if minWait > maxWait {
+		minWait = maxWait
+	}
+	return RetryPolicy{
+		MaxRetries: maxRetries,
+		MaxWait:    maxWait,
+		MinWait:    minWait,
+	}
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// shouldRetry reports whether a request with the given method should be sent again after
+// receiving the given response or transport error.
+func (p RetryPolicy) shouldRetry(method string, r *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		// A POST that died mid-flight may have been processed, so only idempotent verbs retry here.
+		return method != http.MethodPost && isConnectionReset(err)
+	}
+
+	if r == nil || !isRetryableStatus(r.StatusCode) {
+		return false
+	}
+
+	if method != http.MethodPost {
+		return true
+	}
+
+	switch r.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return r.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+// backoff returns the delay before retry number `attempt` (zero-based), using
+// exponential backoff with full jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.MaxWait
+	if attempt < 32 {
+		if exp := p.MinWait << attempt; exp > 0 && exp < ceiling {
+			ceiling = exp
+		}
+	}
+	if ceiling <= p.MinWait {
+		return ceiling
+	}
+	//nolint:gosec // jitter doesn't need a cryptographic source
+	return p.MinWait + time.Duration(rand.Int64N(int64(ceiling-p.MinWait)))
+}
+
+// wait returns how long to sleep before retry number `attempt`, honoring the
+// portal's Retry-After header when present.
+func (p RetryPolicy) wait(attempt int, r *http.Response) time.Duration {
+	if r != nil {
+		if d, ok := parseRetryAfter(r.Header.Get("Retry-After"), time.Now()); ok {
+			if d > p.MaxWait {
+				return p.MaxWait
+			}
+			return d
+		}
+	}
+	return p.backoff(attempt)
+}
+
+// parseRetryAfter understands both forms of the Retry-After header: delay-seconds and HTTP-date.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// rewind returns a copy of req with a fresh body, so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// doRequest sends the request, retrying it according to the client's retry policy. Every
+// attempt waits for the client's rate limiter first.
+// The request body must be rewindable (see http.Request.GetBody) for retries to resend it.
+func (tg *Client) doRequest(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			var err error
+			if r, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := tg.throttle(ctx, req.Method, req.URL.String()); err != nil {
+			return nil, err
+		}
+
+		//nolint:gosec // provider endpoint is intentionally operator-configurable via TG_API_HOST/provider config
+		resp, err := tg.httpClient.Do(r)
+		if attempt >= tg.retry.MaxRetries || !tg.retry.shouldRetry(req.Method, resp, err) {
+			return resp, err
+		}
+
+		wait := tg.retry.wait(attempt, resp)
+		fields := map[string]any{
+			"method":      req.Method,
+			"url":         req.URL.String(),
+			"attempt":     attempt + 1,
+			"max_retries": tg.retry.MaxRetries,
+			"wait":        wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Warn(ctx, "retrying portal request", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package tg
+
+import (
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	p := newRetryPolicy(3, time.Second)
+
+	reply := func(status int, retryAfter string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		resp   *http.Response
+		err    error
+		want   bool
+	}{
+		{name: "get 200", method: http.MethodGet, resp: reply(200, ""), want: false},
+		{name: "get 404", method: http.MethodGet, resp: reply(404, ""), want: false},
+		{name: "get 429", method: http.MethodGet, resp: reply(429, ""), want: true},
+		{name: "put 502", method: http.MethodPut, resp: reply(502, ""), want: true},
+		{name: "delete 503", method: http.MethodDelete, resp: reply(503, ""), want: true},
+		{name: "get conn reset", method: http.MethodGet, err: syscall.ECONNRESET, want: true},
+		{name: "post 429", method: http.MethodPost, resp: reply(429, ""), want: true},
+		{name: "post 502", method: http.MethodPost, resp: reply(502, ""), want: false},
+		{name: "post 503", method: http.MethodPost, resp: reply(503, ""), want: false},
+		{name: "post 503 with retry-after", method: http.MethodPost, resp: reply(503, "2"), want: true},
+		{name: "post conn reset", method: http.MethodPost, err: syscall.ECONNRESET, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.shouldRetry(tt.method, tt.resp, tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := newRetryPolicy(10, 4*time.Second)
+
+	for attempt := 0; attempt < 10; attempt++ {
+		d := p.backoff(attempt)
+		assert.GreaterOrEqual(t, d, p.MinWait)
+		assert.LessOrEqual(t, d, p.MaxWait)
+	}
+}
+
+func TestRetryPolicy_WaitHonorsRetryAfter(t *testing.T) {
+	p := newRetryPolicy(3, 10*time.Second)
+
+	r := &http.Response{StatusCode: 429, Header: http.Header{}}
+	r.Header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, p.wait(0, r))
+
+	r.Header.Set("Retry-After", "120")
+	assert.Equal(t, 10*time.Second, p.wait(0, r), "Retry-After is capped at MaxWait")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("5", now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(7*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}